@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"git-watcher/pkg/analyzer"
+	"git-watcher/pkg/scanner"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	branchesPath      string
+	branchesOutput    string
+	defaultBranch     string
+	staleBranchDays   int
+	staleBranchesOnly bool
+)
+
+var branchesCmd = &cobra.Command{
+	Use:   "branches",
+	Short: "List branches with their distance from the default branch and flag stale ones",
+	RunE:  runBranches,
+}
+
+func init() {
+	branchesCmd.Flags().StringVarP(&branchesPath, "path", "p", ".", "Directory path to scan")
+	branchesCmd.Flags().StringVarP(&branchesOutput, "output", "o", "json", "Output format (json|text)")
+	branchesCmd.Flags().BoolVar(&staleBranchesOnly, "stale", false, "Only list stale branches")
+	addBranchFlags(branchesCmd)
+	rootCmd.AddCommand(branchesCmd)
+}
+
+// addBranchFlags registers the flags that tune the branch inventory.
+func addBranchFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&defaultBranch, "default-branch", "", "Branch others are compared with (default origin/HEAD, main, master or HEAD)")
+	cmd.Flags().IntVar(&staleBranchDays, "stale-after", 90, "Days without a commit before a branch counts as stale")
+}
+
+func branchOptions() analyzer.BranchOptions {
+	return analyzer.BranchOptions{
+		DefaultBranch: defaultBranch,
+		StaleAfter:    time.Duration(staleBranchDays) * 24 * time.Hour,
+	}
+}
+
+func runBranches(cmd *cobra.Command, args []string) error {
+	repos, err := scanner.NewGitScanner().ScanDirectory(branchesPath)
+	if err != nil {
+		return fmt.Errorf("failed to scan directory: %w", err)
+	}
+	if len(repos) == 0 {
+		fmt.Println("No Git repositories found")
+		return nil
+	}
+
+	reports := make(map[string]*analyzer.BranchReport)
+	for _, repo := range repos {
+		report, err := analyzer.NewGitAnalyzer(repo).GetBranches(branchOptions())
+		if err != nil {
+			fmt.Printf("Failed to list branches of %s: %v\n", repo, err)
+			continue
+		}
+		if staleBranchesOnly {
+			stale := []analyzer.BranchInfo{}
+			for _, b := range report.Branches {
+				if b.Stale {
+					stale = append(stale, b)
+				}
+			}
+			report.Branches = stale
+		}
+		reports[repo] = report
+	}
+
+	switch branchesOutput {
+	case "json":
+		jsonOutput, err := json.MarshalIndent(reports, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to generate JSON output: %w", err)
+		}
+		fmt.Println(string(jsonOutput))
+	case "text":
+		for repo, report := range reports {
+			fmt.Printf("\n=== Repository: %s (default %s, %d merged, %d stale) ===\n", repo, report.Default, report.Merged, report.Stale)
+			for _, b := range report.Branches {
+				status := "unmerged"
+				if b.Merged {
+					status = "merged"
+				}
+				if b.Stale {
+					status += ", stale"
+				}
+				fmt.Printf("  %-30s %s %s +%d -%d %s (%s): %s\n",
+					b.Name, b.LastCommit.Format("2006-01-02"), b.Hash[:7], b.Ahead, b.Behind, status, b.Author, b.Subject)
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported output format: %s", branchesOutput)
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"git-watcher/pkg/analyzer"
+	"git-watcher/pkg/scanner"
+	"git-watcher/pkg/stats"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	compareOutput  string
+	comparePeriods int
+)
+
+var compareCmd = &cobra.Command{
+	Use:   "compare [path...]",
+	Short: "Compare repositories side by side, normalised per active contributor",
+	Long:  `Compare the repositories found under the given paths (default: the current directory) by commit volume, authors, late-night and weekend ratios, busiest hours and trend.`,
+	RunE:  runCompare,
+}
+
+func init() {
+	compareCmd.Flags().StringVarP(&compareOutput, "output", "o", "text", "Output format (json|text|markdown)")
+	compareCmd.Flags().IntVar(&comparePeriods, "periods", 12, "Number of trend periods compared (0 for all)")
+	addStatsFlags(compareCmd)
+	rootCmd.AddCommand(compareCmd)
+}
+
+func runCompare(cmd *cobra.Command, args []string) error {
+	opts, err := statsOptions()
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		args = []string{"."}
+	}
+
+	commitsByRepo := make(map[string][]analyzer.CommitInfo)
+	for _, path := range args {
+		repos, err := scanner.NewGitScanner().ScanDirectory(path)
+		if err != nil {
+			return fmt.Errorf("failed to scan directory: %w", err)
+		}
+		for _, repo := range repos {
+			commits, err := analyzer.NewGitAnalyzer(repo).GetCommitInfo()
+			if err != nil {
+				fmt.Printf("Failed to analyze repository %s: %v\n", repo, err)
+				continue
+			}
+			commitsByRepo[repo] = stats.ExcludeCommits(commits, opts.ExcludeCommits)
+		}
+	}
+	if len(commitsByRepo) < 2 {
+		return fmt.Errorf("found %d repositories, at least two are needed to compare", len(commitsByRepo))
+	}
+
+	comparison := stats.Compare(commitsByRepo, opts, comparePeriods)
+	switch compareOutput {
+	case "json":
+		jsonOutput, err := json.MarshalIndent(comparison, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to generate JSON output: %w", err)
+		}
+		fmt.Println(string(jsonOutput))
+	case "text", "markdown":
+		markdown := compareOutput == "markdown"
+		header, rows := comparisonTable(comparison)
+		printTable(header, rows, markdown)
+		fmt.Printf("\nCommits per active author by %s:\n\n", comparison.Bucket)
+		header, rows = comparisonTrendTable(comparison)
+		printTable(header, rows, markdown)
+	default:
+		return fmt.Errorf("unsupported output format: %s", compareOutput)
+	}
+	return nil
+}
+
+func comparisonTable(c stats.Comparison) ([]string, [][]string) {
+	header := []string{"Repository", "Commits", "Authors", "Active", fmt.Sprintf("Commits/active (%dd)", c.WindowDays), "Late-night", "Weekend", "Peak hours"}
+	var rows [][]string
+	for _, r := range c.Repositories {
+		peaks := make([]string, 0, 3)
+		for _, h := range r.PeakHours(3) {
+			peaks = append(peaks, fmt.Sprintf("%02d", h))
+		}
+		rows = append(rows, []string{
+			r.Repository,
+			fmt.Sprint(r.Commits),
+			fmt.Sprint(r.Authors),
+			fmt.Sprint(r.ActiveAuthors),
+			fmt.Sprintf("%.1f", r.CommitsPerActiveAuthor),
+			fmt.Sprintf("%.1f%%", r.LateNightRatio*100),
+			fmt.Sprintf("%.1f%%", r.WeekendRatio*100),
+			strings.Join(peaks, " "),
+		})
+	}
+	return header, rows
+}
+
+// comparisonTrendTable has one row per period and one column per
+// repository.
+func comparisonTrendTable(c stats.Comparison) ([]string, [][]string) {
+	header := []string{"Period"}
+	for _, r := range c.Repositories {
+		header = append(header, r.Repository)
+	}
+	var rows [][]string
+	for i, period := range c.Periods {
+		row := []string{period}
+		for _, r := range c.Repositories {
+			row = append(row, fmt.Sprintf("%.1f", r.Trend[i]))
+		}
+		rows = append(rows, row)
+	}
+	return header, rows
+}
+
+// printTable prints rows under header as aligned columns, or as a Markdown
+// table.
+func printTable(header []string, rows [][]string, markdown bool) {
+	if markdown {
+		fmt.Printf("| %s |\n", strings.Join(header, " | "))
+		fmt.Printf("|%s\n", strings.Repeat(" --- |", len(header)))
+		for _, row := range rows {
+			fmt.Printf("| %s |\n", strings.Join(row, " | "))
+		}
+		return
+	}
+	widths := make([]int, len(header))
+	for _, row := range append([][]string{header}, rows...) {
+		for i, cell := range row {
+			if len(cell) > widths[i] {
+				widths[i] = len(cell)
+			}
+		}
+	}
+	for _, row := range append([][]string{header}, rows...) {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			if i == 0 {
+				cells[i] = fmt.Sprintf("%-*s", widths[i], cell)
+			} else {
+				cells[i] = fmt.Sprintf("%*s", widths[i], cell)
+			}
+		}
+		fmt.Println(strings.Join(cells, "  "))
+	}
+}
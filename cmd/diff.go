@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"git-watcher/pkg/snapshot"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	diffOutput      string
+	diffSnapshotDir string
+	diffList        bool
+	diffAll         bool
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff [old] [new]",
+	Short: "Show what changed between two snapshots",
+	Long: `Compare two snapshots saved with --snapshot: new and removed repositories, new authors, changes in commit counts and in the late-night share.
+
+Snapshots are given by ID, by a unique ID prefix such as a date (20240601) or by file path. Without arguments the two latest snapshots are compared; with one, that snapshot is compared with the latest.`,
+	Args: cobra.MaximumNArgs(2),
+	RunE: runDiff,
+}
+
+func init() {
+	diffCmd.Flags().StringVarP(&diffOutput, "output", "o", "text", "Output format (json|text)")
+	diffCmd.Flags().StringVar(&diffSnapshotDir, "snapshot-dir", snapshot.DefaultDir(), "Snapshot directory")
+	diffCmd.Flags().BoolVar(&diffList, "list", false, "List the stored snapshots instead")
+	diffCmd.Flags().BoolVar(&diffAll, "all", false, "Also list repositories without changes in text output")
+	rootCmd.AddCommand(diffCmd)
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	store := &snapshot.Store{Dir: diffSnapshotDir}
+	ids, err := store.List()
+	if err != nil {
+		return err
+	}
+	if diffList {
+		for _, id := range ids {
+			fmt.Println(id)
+		}
+		return nil
+	}
+
+	refs := args
+	switch len(args) {
+	case 0:
+		if len(ids) < 2 {
+			return fmt.Errorf("found %d snapshots in %s, at least two are needed to diff", len(ids), diffSnapshotDir)
+		}
+		refs = ids[len(ids)-2:]
+	case 1:
+		if len(ids) == 0 {
+			return fmt.Errorf("no snapshots in %s", diffSnapshotDir)
+		}
+		refs = []string{args[0], ids[len(ids)-1]}
+	}
+	from, err := store.Load(refs[0])
+	if err != nil {
+		return err
+	}
+	to, err := store.Load(refs[1])
+	if err != nil {
+		return err
+	}
+	diff, err := snapshot.Compare(from, to)
+	if err != nil {
+		return err
+	}
+
+	switch diffOutput {
+	case "json":
+		jsonOutput, err := json.MarshalIndent(diff, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to generate JSON output: %w", err)
+		}
+		fmt.Println(string(jsonOutput))
+	case "text":
+		printDiff(diff)
+	default:
+		return fmt.Errorf("unsupported output format: %s", diffOutput)
+	}
+	return nil
+}
+
+func printDiff(diff snapshot.Diff) {
+	fmt.Printf("Changes from %s (%s) to %s (%s)\n",
+		diff.From, diff.FromTime.Format("2006-01-02 15:04"), diff.To, diff.ToTime.Format("2006-01-02 15:04"))
+	for _, repo := range diff.NewRepositories {
+		fmt.Printf("\n+ %s (new repository)\n", repo)
+	}
+	for _, repo := range diff.RemovedRepositories {
+		fmt.Printf("\n- %s (no longer found)\n", repo)
+	}
+
+	changed := 0
+	repos := diff.Repositories
+	if diff.Aggregate != nil {
+		all := *diff.Aggregate
+		all.Repository = "All repositories"
+		repos = append(repos, all)
+	}
+	for _, r := range repos {
+		if !r.Changed() {
+			if diffAll {
+				fmt.Printf("\n  %s: no changes\n", r.Repository)
+			}
+			continue
+		}
+		changed++
+		fmt.Printf("\n  %s\n", r.Repository)
+		fmt.Printf("    Commits: %d -> %d (%+d)\n", r.CommitsBefore, r.CommitsAfter, r.CommitsAfter-r.CommitsBefore)
+		if r.LateNightShareBefore != r.LateNightShareAfter {
+			fmt.Printf("    Late-night share: %.1f%% -> %.1f%% (%+.1f points)\n",
+				r.LateNightShareBefore*100, r.LateNightShareAfter*100, (r.LateNightShareAfter-r.LateNightShareBefore)*100)
+		}
+		if len(r.NewAuthors) > 0 {
+			fmt.Printf("    New authors: %s\n", strings.Join(r.NewAuthors, ", "))
+		}
+		for _, a := range r.Authors {
+			fmt.Printf("    %-24s %d -> %d (%+d)\n", a.Author, a.Before, a.After, a.After-a.Before)
+		}
+	}
+	if changed == 0 && len(diff.NewRepositories) == 0 && len(diff.RemovedRepositories) == 0 {
+		fmt.Println("\nNo changes")
+	}
+}
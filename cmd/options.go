@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"fmt"
+	"path"
+	"time"
+
+	"git-watcher/pkg/policy"
+	"git-watcher/pkg/stats"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	statsTop           int
+	hotspotWindowDays  int
+	couplingMinSupport int
+	heatmapByAuthor    bool
+	trendBucket        string
+	timeZone           string
+	timeZoneConfig     string
+	policyPath         string
+	holidayCalendars   []string
+	wellbeing          stats.WellbeingThresholds
+	cohortHorizons     []int
+	inactiveDays       int
+	outlierLines       int64
+	commitURL          string
+	excludeCommits     []string
+	commitTypes        []string
+	messageRules       string
+	releasePattern     string
+	releaseSemver      bool
+)
+
+// addStatsFlags registers the flags that tune statistics on commands that
+// compute them.
+func addStatsFlags(cmd *cobra.Command) {
+	defaults := stats.DefaultOptions()
+	cmd.Flags().IntVar(&statsTop, "top", defaults.Top, "Number of entries kept in ranked statistics (0 for all)")
+	cmd.Flags().IntVar(&hotspotWindowDays, "hotspot-window", 0, "Only rank hotspots over the last N days of history (0 for all)")
+	cmd.Flags().IntVar(&couplingMinSupport, "coupling-min-support", defaults.CouplingMinSupport, "Shared commits needed before two files count as coupled")
+	cmd.Flags().BoolVar(&heatmapByAuthor, "heatmap-by-author", false, "Include a weekday/hour heatmap per author")
+	cmd.Flags().StringVar(&trendBucket, "trend-bucket", string(defaults.TrendBucket), "Bucket size of trend series (day|week|month|quarter)")
+	cmd.Flags().StringVar(&timeZone, "timezone", "author", "Clock for time statistics: author (recorded offset) or an IANA zone such as Europe/Berlin")
+	cmd.Flags().StringVar(&timeZoneConfig, "timezone-config", "", "YAML file mapping authors to IANA zones")
+	cmd.Flags().StringVar(&policyPath, "policy", "", "YAML working-hours policy (work days, hours, breaks, holidays per team or author)")
+	cmd.Flags().StringArrayVar(&holidayCalendars, "holidays", nil, "Holiday calendar as [REGION=]path to an .ics or YAML date list, for the --policy policies of that region (repeatable)")
+	cmd.Flags().Float64Var(&wellbeing.OutOfHoursShare, "wellbeing-out-of-hours", defaults.Wellbeing.OutOfHoursShare, "Share of out-of-hours commits that flags an author")
+	cmd.Flags().IntVar(&wellbeing.Streak, "wellbeing-streak", defaults.Wellbeing.Streak, "Consecutive commit days that flag an author")
+	cmd.Flags().IntVar(&wellbeing.TimeOffCommits, "wellbeing-time-off", defaults.Wellbeing.TimeOffCommits, "Commits on vacations and holidays that flag an author")
+	cmd.Flags().Float64Var(&wellbeing.RecentIncrease, "wellbeing-recent-increase", defaults.Wellbeing.RecentIncrease, "Rise of the recent out-of-hours share over an author's baseline that flags them")
+	cmd.Flags().IntVar(&wellbeing.RecentDays, "wellbeing-recent-days", defaults.Wellbeing.RecentDays, "Days of recent history compared with the baseline")
+	cmd.Flags().IntSliceVar(&cohortHorizons, "cohort-horizons", defaults.CohortHorizons, "Months after their first commit at which newcomers are checked for retention")
+	cmd.Flags().IntVar(&inactiveDays, "inactive-after", int(defaults.InactiveAfter/(24*time.Hour)), "Days without a commit before an author counts as inactive")
+	cmd.Flags().Int64Var(&outlierLines, "outlier-lines", defaults.OutlierLines, "Smallest commit, in changed lines, flagged as a size outlier")
+	cmd.Flags().StringVar(&commitURL, "commit-url", "", "Link template for commits, {hash} is replaced (e.g. https://github.com/org/repo/commit/{hash})")
+	cmd.Flags().StringSliceVar(&excludeCommits, "exclude-commit", nil, "Hash prefix of a commit to leave out of all statistics (repeatable)")
+	cmd.Flags().StringSliceVar(&commitTypes, "commit-types", nil, "Conventional Commits types that count as compliant (default any)")
+	cmd.Flags().StringVar(&messageRules, "message-rules", "", "YAML rules for scoring commit messages (lengths, banned subjects, rule weights)")
+	cmd.Flags().StringVar(&releasePattern, "release-pattern", "", "Glob selecting the tags that count as releases (e.g. 'v2.*', default all tags)")
+	cmd.Flags().BoolVar(&releaseSemver, "release-semver", false, "Only count tags that are semantic versions as releases")
+}
+
+func statsOptions() (stats.Options, error) {
+	opts := stats.DefaultOptions()
+	opts.Top = statsTop
+	opts.HotspotWindow = time.Duration(hotspotWindowDays) * 24 * time.Hour
+	opts.CouplingMinSupport = couplingMinSupport
+	opts.HeatmapByAuthor = heatmapByAuthor
+	opts.Wellbeing = wellbeing
+	opts.CohortHorizons = cohortHorizons
+	opts.InactiveAfter = time.Duration(inactiveDays) * 24 * time.Hour
+	opts.OutlierLines = outlierLines
+	opts.CommitURL = commitURL
+	opts.ExcludeCommits = excludeCommits
+	opts.CommitTypes = commitTypes
+	if _, err := path.Match(releasePattern, ""); err != nil {
+		return opts, fmt.Errorf("invalid --release-pattern %q: %w", releasePattern, err)
+	}
+	opts.ReleasePattern = releasePattern
+	opts.ReleaseSemver = releaseSemver
+	if messageRules != "" {
+		rules, err := stats.LoadMessageRules(messageRules)
+		if err != nil {
+			return opts, err
+		}
+		opts.MessageRules = rules
+	}
+	bucket, err := stats.ParseTrendBucket(trendBucket)
+	if err != nil {
+		return opts, err
+	}
+	opts.TrendBucket = bucket
+	tz, err := stats.LoadTimeZone(timeZone, timeZoneConfig)
+	if err != nil {
+		return opts, err
+	}
+	opts.TimeZone = tz
+	if policyPath != "" {
+		policies, err := policy.Load(policyPath)
+		if err != nil {
+			return opts, err
+		}
+		opts.Policies = policies
+	}
+	if len(holidayCalendars) > 0 {
+		if opts.Policies == nil {
+			opts.Policies = policy.NewSet()
+		}
+		if err := opts.Policies.LoadHolidays(holidayCalendars); err != nil {
+			return opts, err
+		}
+	}
+	return opts, nil
+}
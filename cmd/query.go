@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"git-watcher/pkg/database"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	queryDatabase string
+	queryOutput   string
+	querySchema   bool
+)
+
+var queryCmd = &cobra.Command{
+	Use:   "query [sql]",
+	Short: "Run a read-only SQL query against a database exported with --sqlite",
+	Long: `Run a read-only SQL query against a database exported with --sqlite and print the result.
+
+The commit_details view joins commits with their repository and author; the statistics table holds each statistic of the latest export as JSON, for use with json_extract() and json_each(). --schema prints the documented schema.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runQuery,
+}
+
+func init() {
+	queryCmd.Flags().StringVar(&queryDatabase, "db", "git-watcher.db", "SQLite database written by --sqlite")
+	queryCmd.Flags().StringVarP(&queryOutput, "output", "o", "text", "Output format (json|text|markdown)")
+	queryCmd.Flags().BoolVar(&querySchema, "schema", false, "Print the database schema instead")
+	rootCmd.AddCommand(queryCmd)
+}
+
+func runQuery(cmd *cobra.Command, args []string) error {
+	if querySchema {
+		fmt.Print(database.Schema)
+		return nil
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("no query given")
+	}
+	db, err := database.OpenReadOnly(queryDatabase)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	columns, rows, err := db.Query(args[0])
+	if err != nil {
+		return err
+	}
+	switch queryOutput {
+	case "json":
+		records := make([]map[string]string, 0, len(rows))
+		for _, row := range rows {
+			record := make(map[string]string, len(columns))
+			for i, column := range columns {
+				record[column] = row[i]
+			}
+			records = append(records, record)
+		}
+		jsonOutput, err := json.MarshalIndent(records, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to generate JSON output: %w", err)
+		}
+		fmt.Println(string(jsonOutput))
+	case "text", "markdown":
+		printTable(columns, rows, queryOutput == "markdown")
+	default:
+		return fmt.Errorf("unsupported output format: %s", queryOutput)
+	}
+	return nil
+}
@@ -3,17 +3,40 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 
 	"git-watcher/pkg/analyzer"
+	"git-watcher/pkg/database"
+	"git-watcher/pkg/policy"
 	"git-watcher/pkg/scanner"
+	"git-watcher/pkg/snapshot"
 	"git-watcher/pkg/stats"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 var (
-	rootPath string
-	output   string
+	rootPath           string
+	output             string
+	blame              bool
+	blamePaths         []string
+	blameInclude       []string
+	blameExclude       []string
+	blameCache         string
+	ticket             string
+	ticketRegexp       []string
+	gpgKeyring         string
+	allowedSigners     string
+	requireSignedSince string
+	aggregate          bool
+	saveSnapshot       bool
+	snapshotDir        string
+	sqlitePath         string
 )
 
 var rootCmd = &cobra.Command{
@@ -30,9 +53,52 @@ func Execute() error {
 func init() {
 	rootCmd.Flags().StringVarP(&rootPath, "path", "p", ".", "Directory path to scan")
 	rootCmd.Flags().StringVarP(&output, "output", "o", "json", "Output format (json|text)")
+	rootCmd.Flags().BoolVar(&blame, "blame", false, "Blame HEAD to report current code ownership")
+	rootCmd.Flags().StringSliceVar(&blamePaths, "blame-path", nil, "Path prefixes to blame (default: whole tree)")
+	rootCmd.Flags().StringSliceVar(&blameInclude, "blame-include", nil, "Glob patterns of files to blame")
+	rootCmd.Flags().StringSliceVar(&blameExclude, "blame-exclude", nil, "Glob patterns of files to skip when blaming")
+	rootCmd.Flags().StringVar(&blameCache, "blame-cache", analyzer.DefaultBlameCacheDir(), "Blame cache directory (empty to disable)")
+	rootCmd.Flags().StringVar(&ticket, "ticket", "", "List the commits referencing this ticket in every repository instead of statistics")
+	rootCmd.Flags().StringArrayVar(&ticketRegexp, "ticket-pattern", analyzer.DefaultTicketPatterns, "Regular expression matching ticket references, first group is the ticket (repeatable)")
+	rootCmd.Flags().StringVar(&gpgKeyring, "gpg-keyring", "", "GPG public keyring (armored or binary) to verify commit signatures against")
+	rootCmd.Flags().StringVar(&allowedSigners, "allowed-signers", "", "SSH allowed signers file to verify commit signatures against")
+	rootCmd.Flags().StringVar(&requireSignedSince, "require-signed-since", "", "Fail when a commit on or after this date (YYYY-MM-DD) is unsigned or has an invalid signature; with --gpg-keyring or --allowed-signers, when it is not signed by a trusted key")
+	rootCmd.Flags().BoolVar(&aggregate, "aggregate", false, "Also compute statistics over the commits of all repositories together, unifying authors by email; JSON output then nests the repositories under \"repositories\" next to \"aggregate\"")
+	rootCmd.Flags().BoolVar(&saveSnapshot, "snapshot", false, "Store the results of this run in the snapshot directory, for the diff command")
+	rootCmd.Flags().StringVar(&snapshotDir, "snapshot-dir", snapshot.DefaultDir(), "Snapshot directory")
+	rootCmd.Flags().StringVar(&sqlitePath, "sqlite", "", "Also export repositories, commits, authors, file changes and statistics to this SQLite database, updating it incrementally")
+	addStatsFlags(rootCmd)
+}
+
+type ticketCommit struct {
+	Hash    string `json:"hash"`
+	Author  string `json:"author"`
+	Date    string `json:"date"`
+	Subject string `json:"subject"`
 }
 
 func run(cmd *cobra.Command, args []string) error {
+	opts, err := statsOptions()
+	if err != nil {
+		return err
+	}
+	ticketPatterns, err := analyzer.CompileTicketPatterns(ticketRegexp)
+	if err != nil {
+		return err
+	}
+	verifier, err := analyzer.LoadSignatureVerifier(gpgKeyring, allowedSigners)
+	if err != nil {
+		return err
+	}
+	var signedSince time.Time
+	if requireSignedSince != "" {
+		signedSince, err = time.ParseInLocation("2006-01-02", requireSignedSince, time.Local)
+		if err != nil {
+			return fmt.Errorf("invalid --require-signed-since %q: %w", requireSignedSince, err)
+		}
+	}
+	unsigned := 0
+
 	gitScanner := scanner.NewGitScanner()
 	repos, err := gitScanner.ScanDirectory(rootPath)
 	if err != nil {
@@ -44,94 +110,446 @@ func run(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	var db *database.DB
+	if sqlitePath != "" && ticket == "" {
+		if db, err = database.Open(sqlitePath); err != nil {
+			return err
+		}
+		defer db.Close()
+	}
+
 	allStats := make(map[string]interface{})
+	commitsByRepo := make(map[string][]analyzer.CommitInfo)
 
 	for _, repo := range repos {
 		fmt.Printf("Analyzing repository: %s\n", repo)
 		fmt.Println("Large repositories may take time")
 
-		analyzer := analyzer.NewGitAnalyzer(repo)
-		commits, err := analyzer.GetCommitInfo()
+		gitAnalyzer := analyzer.NewGitAnalyzer(repo)
+		gitAnalyzer.SetTicketPatterns(ticketPatterns)
+		gitAnalyzer.SetSignatureVerifier(verifier)
+		commits, err := gitAnalyzer.GetCommitInfo()
 		if err != nil {
 			fmt.Printf("Failed to analyze repository %s: %v\n", repo, err)
 			continue
 		}
+		commits = stats.ExcludeCommits(commits, opts.ExcludeCommits)
+		if !signedSince.IsZero() {
+			for _, c := range stats.UnsignedSince(commits, signedSince, verifier != nil) {
+				fmt.Fprintf(os.Stderr, "%s: %s %s %s is %s\n", repo, c.Hash[:7], c.Date.Format("2006-01-02"), c.Author, c.Signature.Status)
+				unsigned++
+			}
+		}
 
-		calculator := stats.NewStatsCalculator()
+		if ticket != "" {
+			matched := []ticketCommit{}
+			for _, c := range stats.CommitsForTicket(commits, ticket) {
+				matched = append(matched, ticketCommit{
+					Hash:    c.Hash,
+					Author:  c.Author,
+					Date:    c.Date.Format("2006-01-02 15:04:05"),
+					Subject: strings.SplitN(strings.TrimSpace(c.Message), "\n", 2)[0],
+				})
+			}
+			allStats[repo] = matched
+			continue
+		}
+
+		commitsByRepo[repo] = commits
+		calculator := stats.NewStatsCalculatorWithOptions(opts)
 		repoStats := calculator.CalculateAll(commits)
 
-		allStats[repo] = map[string]interface{}{
+		repoData := map[string]interface{}{
 			"total_commits": len(commits),
 			"statistics":    repoStats,
 		}
+
+		if blame {
+			ownership, err := gitAnalyzer.GetOwnership(analyzer.BlameOptions{
+				Paths:    blamePaths,
+				Include:  blameInclude,
+				Exclude:  blameExclude,
+				CacheDir: blameCache,
+			})
+			if err != nil {
+				fmt.Printf("Failed to blame repository %s: %v\n", repo, err)
+			} else {
+				repoData["ownership"] = ownership
+			}
+		}
+
+		allStats[repo] = repoData
+
+		if db != nil {
+			if err := exportRepository(db, repo, commits, repoStats); err != nil {
+				fmt.Printf("Failed to export repository %s: %v\n", repo, err)
+			}
+		}
+	}
+
+	// the aggregate is kept apart from allStats, where any key can be the
+	// path of a repository
+	var aggregateStats map[string]interface{}
+	if aggregate && ticket == "" && len(commitsByRepo) > 0 {
+		merged := stats.AggregateCommits(commitsByRepo)
+		aggregateStats = map[string]interface{}{
+			"repositories":  len(commitsByRepo),
+			"total_commits": len(merged),
+			"statistics":    stats.NewStatsCalculatorWithOptions(opts).CalculateAll(merged),
+		}
+	}
+
+	if saveSnapshot && ticket == "" {
+		if err := storeSnapshot(cmd, allStats, aggregateStats); err != nil {
+			return err
+		}
 	}
 
 	switch output {
 	case "json":
-		jsonOutput, err := json.MarshalIndent(allStats, "", "  ")
+		var result interface{} = allStats
+		if aggregateStats != nil {
+			result = map[string]interface{}{"repositories": allStats, "aggregate": aggregateStats}
+		}
+		jsonOutput, err := json.MarshalIndent(result, "", "  ")
 		if err != nil {
 			return fmt.Errorf("failed to generate JSON output: %w", err)
 		}
 		fmt.Println(string(jsonOutput))
 	case "text":
-		printTextOutput(allStats)
+		if ticket != "" {
+			printTicketCommits(allStats)
+			break
+		}
+		printTextOutput(allStats, aggregateStats, opts.Policies)
 	default:
 		return fmt.Errorf("unsupported output format: %s", output)
 	}
 
+	if unsigned > 0 {
+		if verifier != nil {
+			return fmt.Errorf("%d commits without a valid signature by a trusted key since %s", unsigned, requireSignedSince)
+		}
+		return fmt.Errorf("%d unsigned or invalid commits since %s", unsigned, requireSignedSince)
+	}
+	return nil
+}
+
+func exportRepository(db *database.DB, repo string, commits []analyzer.CommitInfo, repoStats map[string]interface{}) error {
+	path, err := filepath.Abs(repo)
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+	result, err := db.ExportRepository(path, commits, repoStats)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Exported %s: %d new commits, %d updated, %d removed\n", repo, result.Added, result.Updated, result.Removed)
+	return nil
+}
+
+// storeSnapshot saves allStats and aggregateStats, which may be nil, together
+// with the flags the run was started with.
+func storeSnapshot(cmd *cobra.Command, allStats, aggregateStats map[string]interface{}) error {
+	root, err := filepath.Abs(rootPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+	options := make(map[string]string)
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		options[f.Name] = f.Value.String()
+	})
+	store := &snapshot.Store{Dir: snapshotDir}
+	snap, err := store.Save(time.Now(), root, options, allStats, aggregateStats)
+	if err != nil {
+		return fmt.Errorf("failed to save snapshot: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Saved snapshot %s\n", snap.ID)
 	return nil
 }
 
-func printTextOutput(allStats map[string]interface{}) {
+func printTextOutput(allStats, aggregateStats map[string]interface{}, policies *policy.Set) {
 	for repo, repoData := range allStats {
-		fmt.Printf("\n=== Repository: %s ===\n", repo)
+		printRepoText("Repository: "+repo, repoData.(map[string]interface{}), policies)
+	}
+	if aggregateStats != nil {
+		printRepoText(fmt.Sprintf("All repositories (%v)", aggregateStats["repositories"]), aggregateStats, policies)
+	}
+}
 
-		data := repoData.(map[string]interface{})
-		fmt.Printf("Total commits: %v\n", data["total_commits"])
+func printRepoText(title string, data map[string]interface{}, policies *policy.Set) {
+	fmt.Printf("\n=== %s ===\n", title)
+	fmt.Printf("Total commits: %v\n", data["total_commits"])
 
-		stats := data["statistics"].(map[string]interface{})
+	repoStats := data["statistics"].(map[string]interface{})
+
+	if latestCommit := repoStats["latest_commit"]; latestCommit != nil {
+		commit := latestCommit.(analyzer.CommitInfo)
+		fmt.Printf("Latest commit: %s by %s at %s\n",
+			commit.Hash[:7], commit.Author, commit.Date.Format("2006-01-02 15:04:05"))
+	}
 
-		if latestCommit := stats["latest_commit"]; latestCommit != nil {
-			commit := latestCommit.(analyzer.CommitInfo)
-			fmt.Printf("Latest commit: %s by %s at %s\n",
-				commit.Hash[:7], commit.Author, commit.Date.Format("2006-01-02 15:04:05"))
+	if authorCounts := repoStats["commit_count_by_author"]; authorCounts != nil {
+		fmt.Println("\nAuthor statistics:")
+		authors := authorCounts.(map[string]int)
+		for author, count := range authors {
+			fmt.Printf("  %s: %d\n", author, count)
 		}
+	}
 
-		if authorCounts := stats["commit_count_by_author"]; authorCounts != nil {
-			fmt.Println("\nAuthor statistics:")
-			authors := authorCounts.(map[string]int)
+	if lateNight := repoStats["late_night_commits"]; lateNight != nil {
+		lateNightData := lateNight.(map[string]interface{})
+		fmt.Printf("\nLate-night commits (%s): %v\n", policies.LateNight(), lateNightData["total"])
+		if authors := lateNightData["authors"].(map[string]int); len(authors) > 0 {
+			fmt.Println("Late-night authors:")
 			for author, count := range authors {
 				fmt.Printf("  %s: %d\n", author, count)
 			}
 		}
+	}
+
+	if weekend := repoStats["weekend_commits"]; weekend != nil {
+		weekendData := weekend.(map[string]interface{})
+		fmt.Printf("\nWeekend commits: %v\n", weekendData["total"])
+		if authors := weekendData["authors"].(map[string]int); len(authors) > 0 {
+			fmt.Println("Weekend authors:")
+			for author, count := range authors {
+				fmt.Printf("  %s: %d\n", author, count)
+			}
+		}
+	}
+
+	if holiday := repoStats["holiday_commits"]; holiday != nil {
+		holidayData := holiday.(map[string]interface{})
+		fmt.Printf("\nHoliday commits: %v\n", holidayData["total"])
+		for _, h := range holidayData["holidays"].([]stats.HolidayCommitCount) {
+			fmt.Printf("  %s %s: %d\n", h.Date, h.Name, h.Commits)
+		}
+	}
+	if lineCountByAuthor := repoStats["commit_line_count_by_author"]; lineCountByAuthor != nil {
+		fmt.Println("\nLines changed by author:")
+		lineCounts := lineCountByAuthor.(map[string]int64)
+		for author, count := range lineCounts {
+			fmt.Printf("  %s: %d\n", author, count)
+		}
+	}
+
+	if sizes := repoStats["commit_sizes"]; sizes != nil {
+		printCommitSizes(sizes.(stats.CommitSizeReport))
+	}
+
+	if conventional := repoStats["conventional_commits"]; conventional != nil {
+		report := conventional.(stats.ConventionalReport)
+		fmt.Printf("\nConventional Commits: %.1f%% of %d commits compliant, %d breaking\n",
+			report.Compliance*100, report.Commits, report.Breaking)
+		for _, t := range sortedByValue(report.Types) {
+			fmt.Printf("  %s: %d\n", t, report.Types[t])
+		}
+	}
+
+	if tickets := repoStats["ticket_references"]; tickets != nil {
+		report := tickets.(stats.TicketReport)
+		fmt.Printf("\nCommits with a ticket: %d of %d (%.1f%%), %d tickets\n",
+			report.WithTicket, report.Commits, report.Ratio*100, report.Tickets)
+		for _, t := range report.Top {
+			fmt.Printf("  %s: %d commits by %d authors, %s to %s\n", t.Ticket, t.Commits, t.Authors, t.FirstCommit, t.LastCommit)
+		}
+	}
+
+	if reverts := repoStats["reverts"]; reverts != nil {
+		report := reverts.(stats.RevertReport)
+		fmt.Printf("\nReverted commits: %d of %d (%.1f%%), mean time to revert %.1fh\n",
+			report.Reverted, report.Commits, report.RevertRate*100, report.MeanTimeToRevertHours)
+		fmt.Printf("Reverts: %d (%d unresolved), fixup/squash: %d, small fixes: %d\n",
+			report.Reverts, report.Unresolved, report.Fixups, report.Fixes)
+	}
+
+	if quality := repoStats["message_quality"]; quality != nil {
+		report := quality.(stats.MessageQualityReport)
+		fmt.Printf("\nCommit message quality: %.1f average over %d commits\n", report.Average, report.Commits)
+		for _, o := range report.Worst {
+			fmt.Printf("  %s %d %s: %s (%s)\n", o.Hash[:7], o.Score, o.Author, o.Subject, strings.Join(o.Violations, ", "))
+		}
+	}
+
+	if releases := repoStats["releases"]; releases != nil {
+		printReleases(releases.(stats.ReleaseReport))
+	}
+
+	if signatures := repoStats["signatures"]; signatures != nil {
+		report := signatures.(stats.SignatureReport)
+		fmt.Printf("\nSigned commits: %d of %d (%.1f%%), %d valid, %d invalid, %d unknown key\n",
+			report.Commits-report.Unsigned, report.Commits, report.SignedRatio*100, report.Valid, report.Invalid, report.UnknownKey)
+		for author, a := range report.Authors {
+			fmt.Printf("  %s: %d of %d signed, %d invalid\n", author, a.Commits-a.Unsigned, a.Commits, a.Invalid)
+		}
+	}
+
+	if overtime := repoStats["overtime"]; overtime != nil {
+		report := overtime.(stats.OvertimeReport)
+		fmt.Println("\nOut-of-hours commits:")
+		for author, a := range report.Authors {
+			fmt.Printf("  %s: %d of %d, policy %s\n", author, a.OutOfHours, a.Commits, a.Policy)
+		}
+	}
+
+	if wb := repoStats["wellbeing"]; wb != nil {
+		printWellbeing(wb.(stats.WellbeingReport))
+	}
+
+	if offsets := repoStats["utc_offsets_by_author"]; offsets != nil {
+		fmt.Println("\nRecorded UTC offsets by author:")
+		for author, counts := range offsets.(map[string]map[string]int) {
+			fmt.Printf("  %s:", author)
+			for offset, count := range counts {
+				fmt.Printf(" %s=%d", offset, count)
+			}
+			fmt.Println()
+		}
+	}
 
-		if lateNight := stats["late_night_commits"]; lateNight != nil {
-			lateNightData := lateNight.(map[string]interface{})
-			fmt.Printf("\nLate-night commits (23:00-06:00): %v\n", lateNightData["total"])
-			if authors := lateNightData["authors"].(map[string]int); len(authors) > 0 {
-				fmt.Println("Late-night authors:")
-				for author, count := range authors {
-					fmt.Printf("  %s: %d\n", author, count)
-				}
+	if hotspots := repoStats["hotspots"]; hotspots != nil {
+		report := hotspots.(stats.HotspotReport)
+		if len(report.Files) > 0 {
+			fmt.Println("\nHotspots (score changes churn authors):")
+			for _, h := range report.Files {
+				fmt.Printf("  %6.2f %4d %6d %3d  %s\n", h.Score, h.Changes, h.Churn, h.Authors, h.Path)
 			}
 		}
+	}
 
-		if weekend := stats["weekend_commits"]; weekend != nil {
-			weekendData := weekend.(map[string]interface{})
-			fmt.Printf("\nWeekend commits: %v\n", weekendData["total"])
-			if authors := weekendData["authors"].(map[string]int); len(authors) > 0 {
-				fmt.Println("Weekend authors:")
-				for author, count := range authors {
-					fmt.Printf("  %s: %d\n", author, count)
-				}
+	if coupling := repoStats["temporal_coupling"]; coupling != nil {
+		report := coupling.(stats.CouplingReport)
+		if len(report.Pairs) > 0 {
+			fmt.Println("\nTemporal coupling (degree support):")
+			for _, p := range report.Pairs {
+				fmt.Printf("  %5.1f%% %4d  %s <-> %s\n", p.Degree*100, p.Support, p.FileA, p.FileB)
 			}
 		}
-		if lineCountByAuthor := stats["commit_line_count_by_author"]; lineCountByAuthor != nil {
-			fmt.Println("\nLines changed by author:")
-			lineCounts := lineCountByAuthor.(map[string]int64)
-			for author, count := range lineCounts {
-				fmt.Printf("  %s: %d\n", author, count)
+	}
+
+	if ownership := data["ownership"]; ownership != nil {
+		printOwnership(ownership.(*analyzer.OwnershipReport))
+	}
+}
+
+func printOwnership(report *analyzer.OwnershipReport) {
+	fmt.Printf("\nCurrent ownership (%d lines in %d files):\n", report.TotalLines, report.Files)
+	for _, author := range sortedByValue(report.LinesByAuthor) {
+		lines := report.LinesByAuthor[author]
+		fmt.Printf("  %s: %d (%.1f%%)\n", author, lines, percent(lines, report.TotalLines))
+	}
+
+	dirs := make([]string, 0, len(report.Directories))
+	for dir := range report.Directories {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+	fmt.Println("Ownership by directory:")
+	for _, dir := range dirs {
+		owners := report.Directories[dir]
+		total := 0
+		for _, lines := range owners {
+			total += lines
+		}
+		top := sortedByValue(owners)[0]
+		fmt.Printf("  %s: %s %.1f%% of %d lines\n", dir, top, percent(owners[top], total), total)
+	}
+
+	if len(report.Failed) > 0 {
+		files := make([]string, 0, len(report.Failed))
+		for file := range report.Failed {
+			files = append(files, file)
+		}
+		sort.Strings(files)
+		fmt.Printf("Could not blame %d files:\n", len(files))
+		for _, file := range files {
+			fmt.Printf("  %s: %s\n", file, report.Failed[file])
+		}
+	}
+}
+
+func printTicketCommits(allStats map[string]interface{}) {
+	for repo, data := range allStats {
+		matched := data.([]ticketCommit)
+		fmt.Printf("\n=== Repository: %s (%d commits for %s) ===\n", repo, len(matched), ticket)
+		for _, c := range matched {
+			fmt.Printf("%s %s %s %s\n", c.Hash[:7], c.Date, c.Author, c.Subject)
+		}
+	}
+}
+
+func printReleases(report stats.ReleaseReport) {
+	if len(report.Releases) == 0 {
+		return
+	}
+	fmt.Printf("\nReleases: %d, %.1f days apart on average, %d unreleased commits\n",
+		len(report.Releases), report.MeanDaysBetween, report.Unreleased)
+	for _, r := range report.Releases {
+		names := make([]string, 0, len(r.TopAuthors))
+		for _, a := range r.TopAuthors {
+			names = append(names, fmt.Sprintf("%s (%d)", a.Author, a.Commits))
+		}
+		fmt.Printf("  %s %s: %d commits, %d lines, %d authors, +%.1f days: %s\n",
+			r.Name, r.Date, r.Commits, r.Lines, r.Authors, r.DaysSincePrevious, strings.Join(names, ", "))
+	}
+}
+
+func printCommitSizes(report stats.CommitSizeReport) {
+	repo := report.Repository
+	fmt.Printf("\nCommit size (lines): median %d, p90 %d, p99 %d, max %d\n", repo.Median, repo.P90, repo.P99, repo.Max)
+	for _, bucket := range repo.Histogram {
+		fmt.Printf("  %-10s %d\n", bucket.Label, bucket.Commits)
+	}
+	if len(report.Outliers) > 0 {
+		fmt.Printf("Outliers over %d lines:\n", report.OutlierThreshold)
+		for _, o := range report.Outliers {
+			link := o.Hash
+			if o.URL != "" {
+				link = o.URL
 			}
+			fmt.Printf("  %s %d lines in %d files (%s) by %s: %s\n", link, o.Lines, o.Files, o.Kind, o.Author, o.Subject)
+		}
+	}
+}
+
+func printWellbeing(report stats.WellbeingReport) {
+	// lowest score first
+	scores := make(map[string]int, len(report.Authors))
+	for author, wb := range report.Authors {
+		scores[author] = -wb.Score
+	}
+	fmt.Println("\nWellbeing (100 is best):")
+	for _, author := range sortedByValue(scores) {
+		wb := report.Authors[author]
+		fmt.Printf("  %s: %d %s", author, wb.Score, wb.Level)
+		if len(wb.Flags) > 0 {
+			fmt.Printf(" (%s)", strings.Join(wb.Flags, ", "))
 		}
+		fmt.Println()
+	}
+	for team, tw := range report.Teams {
+		fmt.Printf("  team %s: %d, at risk: %d of %d\n", team, tw.Score, len(tw.AtRisk), tw.Members)
+	}
+}
+
+func sortedByValue(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if m[keys[i]] != m[keys[j]] {
+			return m[keys[i]] > m[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
+
+func percent(part, total int) float64 {
+	if total == 0 {
+		return 0
 	}
+	return float64(part) * 100 / float64(total)
 }
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"git-watcher/pkg/analyzer"
+	"git-watcher/pkg/scanner"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	statusPath    string
+	statusOutput  string
+	statusChanged bool
+)
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the branch, working tree changes, stashes and upstream distance of every repository",
+	RunE:  runStatus,
+}
+
+func init() {
+	statusCmd.Flags().StringVarP(&statusPath, "path", "p", ".", "Directory path to scan")
+	statusCmd.Flags().StringVarP(&statusOutput, "output", "o", "text", "Output format (json|text)")
+	statusCmd.Flags().BoolVar(&statusChanged, "changed", false, "Only list repositories with uncommitted, unpushed or stashed work")
+	rootCmd.AddCommand(statusCmd)
+}
+
+func runStatus(cmd *cobra.Command, args []string) error {
+	repos, err := scanner.NewGitScanner().ScanDirectory(statusPath)
+	if err != nil {
+		return fmt.Errorf("failed to scan directory: %w", err)
+	}
+	if len(repos) == 0 {
+		fmt.Println("No Git repositories found")
+		return nil
+	}
+
+	var listed []string
+	statuses := make(map[string]*analyzer.RepoStatus)
+	for _, repo := range repos {
+		status, err := analyzer.NewGitAnalyzer(repo).GetStatus()
+		if err != nil {
+			fmt.Printf("Failed to get status of %s: %v\n", repo, err)
+			continue
+		}
+		if statusChanged && !status.HasLocalWork() {
+			continue
+		}
+		listed = append(listed, repo)
+		statuses[repo] = status
+	}
+
+	switch statusOutput {
+	case "json":
+		jsonOutput, err := json.MarshalIndent(statuses, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to generate JSON output: %w", err)
+		}
+		fmt.Println(string(jsonOutput))
+	case "text":
+		fmt.Printf("%-40s %-20s %-6s %8s %9s %7s %6s %6s  %s\n",
+			"Repository", "Branch", "State", "Modified", "Untracked", "Stashes", "Ahead", "Behind", "Upstream")
+		for _, repo := range listed {
+			s := statuses[repo]
+			state := "clean"
+			if s.Dirty() {
+				state = "dirty"
+			}
+			upstream := s.Upstream
+			if upstream == "" {
+				upstream = "-"
+			}
+			fmt.Printf("%-40s %-20s %-6s %8d %9d %7d %6d %6d  %s\n",
+				repo, s.Branch, state, s.Modified, s.Untracked, s.Stashes, s.Ahead, s.Behind, upstream)
+		}
+	default:
+		return fmt.Errorf("unsupported output format: %s", statusOutput)
+	}
+	return nil
+}
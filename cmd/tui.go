@@ -11,11 +11,17 @@ var tuiCmd = &cobra.Command{
     Use:   "tui",
     Short: "Start terminal UI",
     RunE: func(cmd *cobra.Command, args []string) error {
-        return tui.StartTUI(tuiPath)
+        opts, err := statsOptions()
+        if err != nil {
+            return err
+        }
+        return tui.StartTUI(tuiPath, opts, branchOptions())
     },
 }
 
 func init() {
     tuiCmd.Flags().StringVarP(&tuiPath, "path", "p", ".", "Directory path to scan")
+    addStatsFlags(tuiCmd)
+    addBranchFlags(tuiCmd)
     rootCmd.AddCommand(tuiCmd)
 }
@@ -2,6 +2,7 @@ package analyzer
 
 import (
 	"fmt"
+	"regexp"
 	"runtime"
 	"sync"
 	"time"
@@ -18,14 +19,38 @@ type CommitInfo struct {
 	Message   string
 	Hash      string
 	LineCount int64
+	Files     []FileChange
+	// Conventional is the parsed Conventional Commits header, nil when
+	// the message does not follow the convention.
+	Conventional *ConventionalCommit
+	// Tickets are the issue tracker references found in the message.
+	Tickets []string
+	// FollowUp marks reverts, fixup/squash commits and small fixes of
+	// earlier work, nil for ordinary commits.
+	FollowUp *FollowUp
+	// Signature records whether the commit is signed and, when a verifier
+	// is set, whether the signature is valid.
+	Signature Signature
+	// Parents are the hashes of the parent commits.
+	Parents []string
+	// Tags are the tags pointing at the commit.
+	Tags []Tag
+}
+
+type FileChange struct {
+	Path      string
+	Additions int
+	Deletions int
 }
 
 type GitAnalyzer struct {
-	repoPath string
+	repoPath       string
+	ticketPatterns []*regexp.Regexp
+	verifier       *SignatureVerifier
 }
 
 func NewGitAnalyzer(repoPath string) *GitAnalyzer {
-	return &GitAnalyzer{repoPath: repoPath}
+	return &GitAnalyzer{repoPath: repoPath, ticketPatterns: defaultTicketPatterns}
 }
 
 func (ga *GitAnalyzer) GetCommitInfo() ([]CommitInfo, error) {
@@ -73,23 +98,11 @@ func (ga *GitAnalyzer) GetCommitInfo() ([]CommitInfo, error) {
 					continue
 				}
 
-				stats, err := c.Stats()
+				info, err := ga.newCommitInfo(c)
 				if err != nil {
 					continue
 				}
-				var totalLines int64
-				for _, stat := range stats {
-					totalLines += int64(stat.Addition + stat.Deletion)
-				}
-
-				resultsChan <- CommitInfo{
-					Author:    c.Author.Name,
-					Email:     c.Author.Email,
-					Date:      c.Author.When,
-					Message:   c.Message,
-					Hash:      c.Hash.String(),
-					LineCount: totalLines,
-				}
+				resultsChan <- info
 			}
 		}()
 	}
@@ -106,6 +119,9 @@ func (ga *GitAnalyzer) GetCommitInfo() ([]CommitInfo, error) {
 	for commitInfo := range resultsChan {
 		commits = append(commits, commitInfo)
 	}
+	if err := attachTags(repo, commits); err != nil {
+		return nil, err
+	}
 
 	return commits, nil
 }
@@ -162,7 +178,7 @@ func (ga *GitAnalyzer) GetCommitInfoWithProgress(onProgress func(processed int,
 					continue
 				}
 
-				stats, err := c.Stats()
+				info, err := ga.newCommitInfo(c)
 				if err != nil {
 					processed++
 					if onProgress != nil {
@@ -170,19 +186,8 @@ func (ga *GitAnalyzer) GetCommitInfoWithProgress(onProgress func(processed int,
 					}
 					continue
 				}
-				var totalLines int64
-				for _, stat := range stats {
-					totalLines += int64(stat.Addition + stat.Deletion)
-				}
 
-				resultsChan <- CommitInfo{
-					Author:    c.Author.Name,
-					Email:     c.Author.Email,
-					Date:      c.Author.When,
-					Message:   c.Message,
-					Hash:      c.Hash.String(),
-					LineCount: totalLines,
-				}
+				resultsChan <- info
 				processed++
 				if onProgress != nil {
 					onProgress(processed, total)
@@ -203,6 +208,47 @@ func (ga *GitAnalyzer) GetCommitInfoWithProgress(onProgress func(processed int,
 	for commitInfo := range resultsChan {
 		commits = append(commits, commitInfo)
 	}
+	if err := attachTags(repo, commits); err != nil {
+		return nil, err
+	}
 
 	return commits, nil
 }
+
+func (ga *GitAnalyzer) newCommitInfo(c *object.Commit) (CommitInfo, error) {
+	stats, err := c.Stats()
+	if err != nil {
+		return CommitInfo{}, err
+	}
+
+	var totalLines int64
+	files := make([]FileChange, 0, len(stats))
+	for _, stat := range stats {
+		totalLines += int64(stat.Addition + stat.Deletion)
+		files = append(files, FileChange{
+			Path:      stat.Name,
+			Additions: stat.Addition,
+			Deletions: stat.Deletion,
+		})
+	}
+
+	parents := make([]string, 0, len(c.ParentHashes))
+	for _, p := range c.ParentHashes {
+		parents = append(parents, p.String())
+	}
+
+	return CommitInfo{
+		Author:       c.Author.Name,
+		Email:        c.Author.Email,
+		Date:         c.Author.When,
+		Message:      c.Message,
+		Hash:         c.Hash.String(),
+		LineCount:    totalLines,
+		Files:        files,
+		Conventional: ParseConventionalCommit(c.Message),
+		Tickets:      ExtractTickets(c.Message, ga.ticketPatterns),
+		FollowUp:     ClassifyFollowUp(c.Message),
+		Signature:    ga.verifier.Verify(c),
+		Parents:      parents,
+	}, nil
+}
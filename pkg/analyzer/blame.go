@@ -0,0 +1,285 @@
+package analyzer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// BlameOptions selects the files of HEAD that are blamed.
+//
+// Paths are prefixes relative to the repository root. Include and Exclude
+// are glob patterns: a pattern without a slash is matched against the file
+// name, a pattern ending in a slash is a directory prefix and anything else
+// is matched against the full path.
+type BlameOptions struct {
+	Paths    []string
+	Include  []string
+	Exclude  []string
+	Workers  int
+	CacheDir string
+}
+
+type OwnershipReport struct {
+	Files         int                       `json:"files"`
+	TotalLines    int                       `json:"total_lines"`
+	LinesByAuthor map[string]int            `json:"lines_by_author"`
+	Directories   map[string]map[string]int `json:"directories"`
+	// Failed are the files that could not be blamed, with the reason.
+	Failed map[string]string `json:"failed,omitempty"`
+}
+
+// DefaultBlameCacheDir returns the directory used to cache blame results
+// between runs, or "" when no user cache directory is available.
+func DefaultBlameCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "git-watcher", "blame")
+}
+
+type blameTarget struct {
+	path string
+	blob plumbing.Hash
+}
+
+type blamedFile struct {
+	path    string
+	authors map[string]int
+	err     error
+}
+
+// GetOwnership blames every selected file of HEAD and reports how many of
+// the surviving lines belong to each author, overall and per directory.
+func (ga *GitAnalyzer) GetOwnership(opts BlameOptions) (*OwnershipReport, error) {
+	repo, err := git.PlainOpen(ga.repoPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	ref, err := repo.Head()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get HEAD: %w", err)
+	}
+
+	head, err := repo.CommitObject(ref.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get HEAD commit: %w", err)
+	}
+
+	tree, err := head.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get HEAD tree: %w", err)
+	}
+
+	var targets []blameTarget
+	err = tree.Files().ForEach(func(f *object.File) error {
+		if !opts.selects(f.Name) {
+			return nil
+		}
+		if binary, err := f.IsBinary(); err != nil || binary {
+			return nil
+		}
+		targets = append(targets, blameTarget{path: f.Name, blob: f.Hash})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files: %w", err)
+	}
+
+	numWorkers := opts.Workers
+	if numWorkers <= 0 {
+		numWorkers = runtime.NumCPU()
+	}
+	targetChan := make(chan blameTarget, len(targets))
+	resultsChan := make(chan blamedFile, len(targets))
+	var wg sync.WaitGroup
+	cacheDir := blameCacheDir(opts.CacheDir, ga.repoPath)
+
+	for i := 0; i < numWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var c *object.Commit
+			workerRepo, err := git.PlainOpen(ga.repoPath)
+			if err == nil {
+				c, err = workerRepo.CommitObject(ref.Hash())
+			}
+
+			for t := range targetChan {
+				if err != nil {
+					resultsChan <- blamedFile{path: t.path, err: err}
+					continue
+				}
+				if authors, ok := loadBlameCache(cacheDir, t.path, t.blob); ok {
+					resultsChan <- blamedFile{path: t.path, authors: authors}
+					continue
+				}
+
+				result, blameErr := git.Blame(c, t.path)
+				if blameErr != nil {
+					resultsChan <- blamedFile{path: t.path, err: blameErr}
+					continue
+				}
+				authors := make(map[string]int)
+				for _, line := range result.Lines {
+					authors[line.AuthorName]++
+				}
+				storeBlameCache(cacheDir, t.path, t.blob, authors)
+				resultsChan <- blamedFile{path: t.path, authors: authors}
+			}
+		}()
+	}
+
+	for _, t := range targets {
+		targetChan <- t
+	}
+	close(targetChan)
+
+	wg.Wait()
+	close(resultsChan)
+
+	report := &OwnershipReport{
+		LinesByAuthor: make(map[string]int),
+		Directories:   make(map[string]map[string]int),
+	}
+	for file := range resultsChan {
+		if file.err != nil {
+			if report.Failed == nil {
+				report.Failed = make(map[string]string)
+			}
+			report.Failed[file.path] = file.err.Error()
+			continue
+		}
+		report.Files++
+		if len(file.authors) == 0 {
+			continue
+		}
+		dir := path.Dir(file.path)
+		if report.Directories[dir] == nil {
+			report.Directories[dir] = make(map[string]int)
+		}
+		for author, lines := range file.authors {
+			report.TotalLines += lines
+			report.LinesByAuthor[author] += lines
+			report.Directories[dir][author] += lines
+		}
+	}
+
+	return report, nil
+}
+
+func (o BlameOptions) selects(name string) bool {
+	if len(o.Paths) > 0 {
+		matched := false
+		for _, p := range o.Paths {
+			p = strings.TrimPrefix(path.Clean(filepath.ToSlash(p)), "./")
+			if p == "." || name == p || strings.HasPrefix(name, strings.TrimSuffix(p, "/")+"/") {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if len(o.Include) > 0 && !matchesAny(name, o.Include) {
+		return false
+	}
+	return !matchesAny(name, o.Exclude)
+}
+
+func matchesAny(name string, patterns []string) bool {
+	for _, pattern := range patterns {
+		switch {
+		case strings.HasSuffix(pattern, "/"):
+			if strings.HasPrefix(name, pattern) {
+				return true
+			}
+		case strings.Contains(pattern, "/"):
+			if ok, _ := path.Match(pattern, name); ok {
+				return true
+			}
+		default:
+			if ok, _ := path.Match(pattern, path.Base(name)); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// blameCacheDir is the cache subdirectory of a repository: blame depends on
+// the history of a file, so identical blobs in other repositories must not
+// share entries.
+func blameCacheDir(dir, repoPath string) string {
+	if dir == "" {
+		return ""
+	}
+	if abs, err := filepath.Abs(repoPath); err == nil {
+		repoPath = abs
+	}
+	sum := sha256.Sum256([]byte(repoPath))
+	return filepath.Join(dir, hex.EncodeToString(sum[:8]))
+}
+
+// blameCacheKey names the entry of a file: the same blob at another path
+// has another history.
+func blameCacheKey(name string, blob plumbing.Hash) string {
+	sum := sha256.Sum256([]byte(name + "\x00" + blob.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+func loadBlameCache(dir, name string, blob plumbing.Hash) (map[string]int, bool) {
+	if dir == "" {
+		return nil, false
+	}
+	data, err := os.ReadFile(filepath.Join(dir, blameCacheKey(name, blob)+".json"))
+	if err != nil {
+		return nil, false
+	}
+	var authors map[string]int
+	if err := json.Unmarshal(data, &authors); err != nil {
+		return nil, false
+	}
+	return authors, true
+}
+
+func storeBlameCache(dir, name string, blob plumbing.Hash, authors map[string]int) {
+	if dir == "" {
+		return
+	}
+	key := blameCacheKey(name, blob)
+	data, err := json.Marshal(authors)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return
+	}
+	tmp, err := os.CreateTemp(dir, key+".*.tmp")
+	if err != nil {
+		return
+	}
+	_, werr := tmp.Write(data)
+	cerr := tmp.Close()
+	if werr != nil || cerr != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(dir, key+".json")); err != nil {
+		os.Remove(tmp.Name())
+	}
+}
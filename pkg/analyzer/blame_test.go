@@ -0,0 +1,92 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func TestBlameCache(t *testing.T) {
+	cache := t.TempDir()
+	blob := plumbing.NewHash("0123456789abcdef0123456789abcdef01234567")
+	app, lib := blameCacheDir(cache, "/src/app"), blameCacheDir(cache, "/src/lib")
+	if app == lib {
+		t.Fatalf("repositories share the cache directory %s", app)
+	}
+
+	storeBlameCache(app, "LICENSE", blob, map[string]int{"Alice": 3})
+	if authors, ok := loadBlameCache(app, "LICENSE", blob); !ok || authors["Alice"] != 3 {
+		t.Errorf("loadBlameCache() = %v, %v", authors, ok)
+	}
+	if _, ok := loadBlameCache(lib, "LICENSE", blob); ok {
+		t.Error("the blob was found in another repository's cache")
+	}
+	if _, ok := loadBlameCache(app, "vendor/LICENSE", blob); ok {
+		t.Error("the blob was found at another path")
+	}
+	if _, ok := loadBlameCache("", "LICENSE", blob); ok {
+		t.Error("an empty cache directory was read")
+	}
+
+	// The same file committed by different authors in two repositories
+	owners := make(map[string]string)
+	for _, author := range []string{"Alice", "Bob"} {
+		dir := t.TempDir()
+		repo, err := git.PlainInit(dir, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		wt, _ := repo.Worktree()
+		if err := os.WriteFile(filepath.Join(dir, "LICENSE"), []byte("MIT\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		wt.Add("LICENSE")
+		who := &object.Signature{Name: author, When: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+		if _, err := wt.Commit("Add license", &git.CommitOptions{Author: who, Committer: who}); err != nil {
+			t.Fatal(err)
+		}
+		report, err := NewGitAnalyzer(dir).GetOwnership(BlameOptions{CacheDir: cache})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if report.Files != 1 || len(report.Failed) != 0 {
+			t.Fatalf("%s's report = %+v", author, report)
+		}
+		for name := range report.LinesByAuthor {
+			owners[author] = name
+		}
+	}
+	if owners["Alice"] != "Alice" || owners["Bob"] != "Bob" {
+		t.Errorf("owners = %v", owners)
+	}
+}
+
+func TestBlameOptionsSelects(t *testing.T) {
+	tests := []struct {
+		opts BlameOptions
+		name string
+		want bool
+	}{
+		{BlameOptions{}, "main.go", true},
+		{BlameOptions{Paths: []string{"./pkg"}}, "pkg/stats/stats.go", true},
+		{BlameOptions{Paths: []string{"pkg/"}}, "pkgs/x.go", false},
+		{BlameOptions{Paths: []string{"."}}, "README.md", true},
+		{BlameOptions{Include: []string{"*.go"}}, "pkg/stats/stats.go", true},
+		{BlameOptions{Include: []string{"*.go"}}, "README.md", false},
+		{BlameOptions{Include: []string{"pkg/*/*.go"}}, "pkg/stats/stats.go", true},
+		{BlameOptions{Include: []string{"pkg/*.go"}}, "pkg/stats/stats.go", false},
+		{BlameOptions{Exclude: []string{"vendor/"}}, "vendor/x/y.go", false},
+		{BlameOptions{Exclude: []string{"vendor/"}}, "src/vendor/y.go", true},
+		{BlameOptions{Include: []string{"*.go"}, Exclude: []string{"*_test.go"}}, "pkg/stats/stats_test.go", false},
+	}
+	for _, tt := range tests {
+		if got := tt.opts.selects(tt.name); got != tt.want {
+			t.Errorf("%+v.selects(%q) = %v, want %v", tt.opts, tt.name, got, tt.want)
+		}
+	}
+}
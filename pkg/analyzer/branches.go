@@ -0,0 +1,372 @@
+package analyzer
+
+import (
+	"container/heap"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// BranchOptions configure GetBranches. An empty DefaultBranch is guessed
+// from origin/HEAD, then main, master and finally HEAD; a zero Now is the
+// current time.
+type BranchOptions struct {
+	DefaultBranch string
+	StaleAfter    time.Duration
+	Now           time.Time
+}
+
+type BranchInfo struct {
+	Name       string    `json:"name"`
+	Remote     bool      `json:"remote"`
+	Hash       string    `json:"hash"`
+	LastCommit time.Time `json:"last_commit"`
+	Author     string    `json:"author"`
+	Subject    string    `json:"subject"`
+	// Ahead and Behind count the commits only on the branch and only on
+	// the default branch.
+	Ahead  int  `json:"ahead"`
+	Behind int  `json:"behind"`
+	Merged bool `json:"merged"`
+	Stale  bool `json:"stale"`
+	// AgeDays is the time since the last commit.
+	AgeDays float64 `json:"age_days"`
+}
+
+type BranchReport struct {
+	Default  string       `json:"default"`
+	Branches []BranchInfo `json:"branches"`
+	Merged   int          `json:"merged"`
+	Stale    int          `json:"stale"`
+}
+
+// GetBranches lists every local and remote-tracking branch with its last
+// commit, its distance from the default branch and whether it is merged or
+// stale. Branches are ordered oldest first.
+func (ga *GitAnalyzer) GetBranches(opts BranchOptions) (*BranchReport, error) {
+	repo, err := git.PlainOpen(ga.repoPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository: %w", err)
+	}
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	defaultRef, err := defaultBranch(repo, opts.DefaultBranch)
+	if err != nil {
+		return nil, err
+	}
+	// the default branch's history is walked once; each branch only walks
+	// until it joins it
+	graph := newCommitGraph(repo)
+	onDefault, err := graph.generations(defaultRef.Hash())
+	if err != nil {
+		return nil, err
+	}
+
+	refs, err := repo.References()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branches: %w", err)
+	}
+	report := &BranchReport{Default: defaultRef.Name().Short(), Branches: []BranchInfo{}}
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		name := ref.Name()
+		if ref.Type() != plumbing.HashReference || !(name.IsBranch() || name.IsRemote()) || name == defaultRef.Name() {
+			return nil
+		}
+		c, err := repo.CommitObject(ref.Hash())
+		if err != nil {
+			return nil
+		}
+		ahead, joins, err := graph.walkUntil(c.Hash, onDefault)
+		if err != nil {
+			return err
+		}
+		behind, err := graph.behind(defaultRef.Hash(), joins, onDefault)
+		if err != nil {
+			return err
+		}
+		branch := newBranchInfo(ref, c, now)
+		branch.Ahead, branch.Behind = ahead, behind
+		branch.Merged = branch.Ahead == 0
+		branch.Stale = opts.StaleAfter > 0 && now.Sub(branch.LastCommit) > opts.StaleAfter
+		if branch.Merged {
+			report.Merged++
+		}
+		if branch.Stale {
+			report.Stale++
+		}
+		report.Branches = append(report.Branches, branch)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(report.Branches, func(i, j int) bool {
+		a, b := report.Branches[i], report.Branches[j]
+		if !a.LastCommit.Equal(b.LastCommit) {
+			return a.LastCommit.Before(b.LastCommit)
+		}
+		return a.Name < b.Name
+	})
+	return report, nil
+}
+
+// commitGraph walks commit ancestry, caching the parents of every commit
+// it reads.
+type commitGraph struct {
+	repo    *git.Repository
+	parents map[plumbing.Hash][]plumbing.Hash
+}
+
+func newCommitGraph(repo *git.Repository) *commitGraph {
+	return &commitGraph{repo: repo, parents: make(map[plumbing.Hash][]plumbing.Hash)}
+}
+
+func (g *commitGraph) parentsOf(h plumbing.Hash) ([]plumbing.Hash, error) {
+	if parents, ok := g.parents[h]; ok {
+		return parents, nil
+	}
+	c, err := g.repo.CommitObject(h)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read commit %s: %w", h, err)
+	}
+	g.parents[h] = c.ParentHashes
+	return c.ParentHashes, nil
+}
+
+// ancestors returns from and every commit reachable from it.
+func (g *commitGraph) ancestors(from plumbing.Hash) (map[plumbing.Hash]bool, error) {
+	seen := make(map[plumbing.Hash]bool)
+	stack := []plumbing.Hash{from}
+	for len(stack) > 0 {
+		h := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if seen[h] {
+			continue
+		}
+		seen[h] = true
+		parents, err := g.parentsOf(h)
+		if err != nil {
+			return nil, err
+		}
+		stack = append(stack, parents...)
+	}
+	return seen, nil
+}
+
+// generations returns from and every commit reachable from it, each with a
+// generation number greater than those of its parents.
+func (g *commitGraph) generations(from plumbing.Hash) (map[plumbing.Hash]int, error) {
+	gen := make(map[plumbing.Hash]int)
+	type frame struct {
+		hash     plumbing.Hash
+		expanded bool
+	}
+	stack := []frame{{hash: from}}
+	for len(stack) > 0 {
+		top := &stack[len(stack)-1]
+		if _, done := gen[top.hash]; done {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		parents, err := g.parentsOf(top.hash)
+		if err != nil {
+			return nil, err
+		}
+		if !top.expanded {
+			// number the parents first
+			top.expanded = true
+			for _, p := range parents {
+				if _, done := gen[p]; !done {
+					stack = append(stack, frame{hash: p})
+				}
+			}
+			continue
+		}
+		n := 1
+		for _, p := range parents {
+			if gen[p] >= n {
+				n = gen[p] + 1
+			}
+		}
+		gen[top.hash] = n
+		stack = stack[:len(stack)-1]
+	}
+	return gen, nil
+}
+
+// walkUntil walks back from from without entering the commits of stop. It
+// returns the number of commits it visited and the commits of stop where it
+// ended.
+func (g *commitGraph) walkUntil(from plumbing.Hash, stop map[plumbing.Hash]int) (int, []plumbing.Hash, error) {
+	seen := make(map[plumbing.Hash]bool)
+	var joins []plumbing.Hash
+	stack := []plumbing.Hash{from}
+	for len(stack) > 0 {
+		h := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if seen[h] {
+			continue
+		}
+		seen[h] = true
+		if _, ok := stop[h]; ok {
+			joins = append(joins, h)
+			continue
+		}
+		parents, err := g.parentsOf(h)
+		if err != nil {
+			return 0, nil, err
+		}
+		stack = append(stack, parents...)
+	}
+	return len(seen) - len(joins), joins, nil
+}
+
+// behind counts the commits reachable from tip but not from joins, which
+// must all be numbered in gen. It visits commits newest generation first so
+// that a commit is only counted once all its children are known, and stops
+// as soon as everything left to visit is reachable from joins.
+func (g *commitGraph) behind(tip plumbing.Hash, joins []plumbing.Hash, gen map[plumbing.Hash]int) (int, error) {
+	const (
+		fromTip = 1 << iota
+		fromJoins
+	)
+	flags := make(map[plumbing.Hash]int)
+	queue := &generationQueue{gen: gen}
+	open := 0 // queued commits not reachable from joins
+	mark := func(h plumbing.Hash, f int) {
+		old, seen := flags[h]
+		flags[h] = old | f
+		switch {
+		case !seen:
+			heap.Push(queue, h)
+			if f&fromJoins == 0 {
+				open++
+			}
+		case old&fromJoins == 0 && f&fromJoins != 0 && queue.has(h):
+			open--
+		}
+	}
+	mark(tip, fromTip)
+	for _, h := range joins {
+		mark(h, fromJoins)
+	}
+
+	behind := 0
+	for open > 0 {
+		h := heap.Pop(queue).(plumbing.Hash)
+		f := flags[h]
+		if f&fromJoins == 0 {
+			open--
+			behind++
+		}
+		parents, err := g.parentsOf(h)
+		if err != nil {
+			return 0, err
+		}
+		for _, p := range parents {
+			mark(p, f)
+		}
+	}
+	return behind, nil
+}
+
+// generationQueue is a max-heap of commits by generation number.
+type generationQueue struct {
+	gen    map[plumbing.Hash]int
+	hashes []plumbing.Hash
+	queued map[plumbing.Hash]bool
+}
+
+func (q *generationQueue) has(h plumbing.Hash) bool { return q.queued[h] }
+
+func (q *generationQueue) Len() int           { return len(q.hashes) }
+func (q *generationQueue) Less(i, j int) bool { return q.gen[q.hashes[i]] > q.gen[q.hashes[j]] }
+func (q *generationQueue) Swap(i, j int)      { q.hashes[i], q.hashes[j] = q.hashes[j], q.hashes[i] }
+
+func (q *generationQueue) Push(x interface{}) {
+	if q.queued == nil {
+		q.queued = make(map[plumbing.Hash]bool)
+	}
+	h := x.(plumbing.Hash)
+	q.queued[h] = true
+	q.hashes = append(q.hashes, h)
+}
+
+func (q *generationQueue) Pop() interface{} {
+	h := q.hashes[len(q.hashes)-1]
+	q.hashes = q.hashes[:len(q.hashes)-1]
+	delete(q.queued, h)
+	return h
+}
+
+// aheadBehind counts the commits only in ours and only in theirs.
+func aheadBehind(ours, theirs map[plumbing.Hash]bool) (ahead, behind int) {
+	shared := 0
+	for h := range ours {
+		if theirs[h] {
+			shared++
+		} else {
+			ahead++
+		}
+	}
+	return ahead, len(theirs) - shared
+}
+
+func newBranchInfo(ref *plumbing.Reference, c *object.Commit, now time.Time) BranchInfo {
+	return BranchInfo{
+		Name:       ref.Name().Short(),
+		Remote:     ref.Name().IsRemote(),
+		Hash:       c.Hash.String(),
+		LastCommit: c.Committer.When,
+		Author:     c.Author.Name,
+		Subject:    strings.TrimSpace(strings.SplitN(c.Message, "\n", 2)[0]),
+		AgeDays:    now.Sub(c.Committer.When).Hours() / 24,
+	}
+}
+
+// defaultBranch resolves name as a local or remote-tracking branch, or
+// guesses the default branch when name is empty.
+func defaultBranch(repo *git.Repository, name string) (*plumbing.Reference, error) {
+	if name != "" {
+		for _, candidate := range []plumbing.ReferenceName{
+			plumbing.NewBranchReferenceName(name),
+			plumbing.ReferenceName("refs/remotes/" + name),
+			plumbing.NewRemoteReferenceName("origin", name),
+		} {
+			if ref, err := repo.Reference(candidate, true); err == nil {
+				return ref, nil
+			}
+		}
+		return nil, fmt.Errorf("default branch %q not found", name)
+	}
+
+	if ref, err := repo.Reference(plumbing.NewRemoteHEADReferenceName("origin"), false); err == nil && ref.Type() == plumbing.SymbolicReference {
+		// prefer the local branch tracking origin's default
+		local := plumbing.NewBranchReferenceName(strings.TrimPrefix(ref.Target().Short(), "origin/"))
+		if resolved, err := repo.Reference(local, true); err == nil {
+			return resolved, nil
+		}
+		if resolved, err := repo.Reference(ref.Target(), true); err == nil {
+			return resolved, nil
+		}
+	}
+	for _, branch := range []string{"main", "master"} {
+		if ref, err := repo.Reference(plumbing.NewBranchReferenceName(branch), true); err == nil {
+			return ref, nil
+		}
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get HEAD: %w", err)
+	}
+	return head, nil
+}
@@ -0,0 +1,155 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func TestGetBranches(t *testing.T) {
+	dir := t.TempDir()
+	repo, err := git.PlainInitWithOptions(dir, &git.PlainInitOptions{
+		InitOptions: git.InitOptions{DefaultBranch: plumbing.Master},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wt, _ := repo.Worktree()
+	commit := func(file string, day int) plumbing.Hash {
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(file), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		wt.Add(file)
+		who := &object.Signature{Name: "Alice", When: time.Date(2024, 1, day, 12, 0, 0, 0, time.UTC)}
+		h, err := wt.Commit("Add "+file, &git.CommitOptions{Author: who, Committer: who})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return h
+	}
+	checkout := func(branch string, create bool) {
+		if err := wt.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName(branch), Create: create}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	commit("a", 1)
+	merged := commit("b", 2)
+	checkout("feature", true)
+	commit("c", 3)
+	commit("d", 20)
+	checkout("master", false)
+	commit("e", 21)
+	repo.Storer.SetReference(plumbing.NewHashReference(plumbing.NewBranchReferenceName("done"), merged))
+	repo.Storer.SetReference(plumbing.NewHashReference(plumbing.NewRemoteReferenceName("origin", "old"), merged))
+	repo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{"https://example.com/repo.git"}})
+
+	report, err := NewGitAnalyzer(dir).GetBranches(BranchOptions{
+		StaleAfter: 10 * 24 * time.Hour,
+		Now:        time.Date(2024, 1, 25, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Default != "master" || len(report.Branches) != 3 || report.Merged != 2 || report.Stale != 2 {
+		t.Fatalf("report = %+v", report)
+	}
+	byName := make(map[string]BranchInfo)
+	for _, b := range report.Branches {
+		byName[b.Name] = b
+	}
+	if b := byName["feature"]; b.Ahead != 2 || b.Behind != 1 || b.Merged || b.Stale {
+		t.Errorf("feature = %+v", b)
+	}
+	if b := byName["done"]; b.Ahead != 0 || b.Behind != 1 || !b.Merged || !b.Stale || b.Remote {
+		t.Errorf("done = %+v", b)
+	}
+	if b := byName["origin/old"]; !b.Remote || !b.Merged {
+		t.Errorf("origin/old = %+v", b)
+	}
+
+	if _, err := NewGitAnalyzer(dir).GetBranches(BranchOptions{DefaultBranch: "missing"}); err == nil {
+		t.Error("expected an error for a missing default branch")
+	}
+	report, err = NewGitAnalyzer(dir).GetBranches(BranchOptions{DefaultBranch: "feature"})
+	if err != nil || report.Default != "feature" {
+		t.Fatalf("default feature = %+v, %v", report, err)
+	}
+}
+
+func TestBranchDistanceWithMerges(t *testing.T) {
+	dir := t.TempDir()
+	repo, err := git.PlainInitWithOptions(dir, &git.PlainInitOptions{
+		InitOptions: git.InitOptions{DefaultBranch: plumbing.Master},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wt, _ := repo.Worktree()
+	day := 0
+	commit := func(file string, parents ...plumbing.Hash) plumbing.Hash {
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(file), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		wt.Add(file)
+		day++
+		who := &object.Signature{Name: "Alice", When: time.Date(2024, 1, day, 12, 0, 0, 0, time.UTC)}
+		h, err := wt.Commit("Add "+file, &git.CommitOptions{Author: who, Committer: who, Parents: parents})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return h
+	}
+	branch := func(name string, h plumbing.Hash) {
+		repo.Storer.SetReference(plumbing.NewHashReference(plumbing.NewBranchReferenceName(name), h))
+	}
+
+	// a1 - a2 - a3 ------ m - a4   master
+	//     \    \        /
+	//      \    s1 - s2          merged side branch
+	//       t1 - t2              topic, forked before the merge
+	a1 := commit("a1")
+	a2 := commit("a2")
+	a3 := commit("a3")
+	s1 := commit("s1", a2)
+	s2 := commit("s2")
+	m := commit("m", a3, s2)
+	a4 := commit("a4")
+	t2 := commit("t2", commit("t1", a1))
+	// committing moves master along, so branches are set afterwards
+	branch("master", a4)
+	branch("side", s2)
+	branch("topic", t2)
+	branch("old", s1)
+
+	report, err := NewGitAnalyzer(dir).GetBranches(BranchOptions{DefaultBranch: "master"})
+	if err != nil || len(report.Branches) != 3 {
+		t.Fatalf("report = %+v, %v", report, err)
+	}
+	graph := newCommitGraph(repo)
+	onMaster, _ := graph.ancestors(a4)
+	for _, b := range report.Branches {
+		tip, _ := graph.ancestors(plumbing.NewHash(b.Hash))
+		ahead, behind := aheadBehind(tip, onMaster)
+		if b.Ahead != ahead || b.Behind != behind {
+			t.Errorf("%s: ahead %d behind %d, want %d %d", b.Name, b.Ahead, b.Behind, ahead, behind)
+		}
+	}
+
+	// the branch walk stops where it joins the default branch
+	gen, err := graph.generations(a4)
+	if err != nil || gen[m] != gen[s2]+1 || gen[a4] != gen[m]+1 {
+		t.Fatalf("generations = %v, %v", gen, err)
+	}
+	graph.parents = make(map[plumbing.Hash][]plumbing.Hash)
+	ahead, joins, err := graph.walkUntil(t2, gen)
+	if err != nil || ahead != 2 || len(joins) != 1 || joins[0] != a1 || len(graph.parents) != 2 {
+		t.Errorf("walkUntil(topic) = %d %v %v, read %d commits", ahead, joins, err, len(graph.parents))
+	}
+}
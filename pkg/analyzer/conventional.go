@@ -0,0 +1,70 @@
+package analyzer
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ConventionalCommit is a commit message parsed following the Conventional
+// Commits specification: "type(scope)!: description", an optional body and
+// footers such as "Refs: #123" or "BREAKING CHANGE: ...".
+type ConventionalCommit struct {
+	Type        string
+	Scope       string
+	Breaking    bool
+	Description string
+	Footers     []Footer
+}
+
+type Footer struct {
+	Token string
+	Value string
+}
+
+var (
+	conventionalHeader = regexp.MustCompile(`^([A-Za-z]+)(?:\(([^()\r\n]*)\))?(!)?: (\S.*)$`)
+	footerLine         = regexp.MustCompile(`^(BREAKING[ -]CHANGE|[A-Za-z][A-Za-z-]*)(?:: | #)(.*)$`)
+)
+
+// ParseConventionalCommit parses message, returning nil when its header does
+// not follow the convention. Types are lower-cased.
+func ParseConventionalCommit(message string) *ConventionalCommit {
+	lines := strings.Split(strings.ReplaceAll(strings.TrimSpace(message), "\r\n", "\n"), "\n")
+	m := conventionalHeader.FindStringSubmatch(strings.TrimSpace(lines[0]))
+	if m == nil {
+		return nil
+	}
+	cc := &ConventionalCommit{
+		Type:        strings.ToLower(m[1]),
+		Scope:       strings.TrimSpace(m[2]),
+		Breaking:    m[3] == "!",
+		Description: strings.TrimSpace(m[4]),
+	}
+
+	// footers form the last paragraph after the header
+	start := len(lines)
+	for start > 1 && strings.TrimSpace(lines[start-1]) != "" {
+		start--
+	}
+	if start == 1 {
+		return cc
+	}
+	for _, line := range lines[start:] {
+		f := footerLine.FindStringSubmatch(line)
+		if f == nil {
+			// continuation of the previous footer's value
+			if n := len(cc.Footers); n > 0 {
+				cc.Footers[n-1].Value += "\n" + line
+				continue
+			}
+			return cc
+		}
+		footer := Footer{Token: f[1], Value: f[2]}
+		if footer.Token == "BREAKING-CHANGE" || footer.Token == "BREAKING CHANGE" {
+			footer.Token = "BREAKING CHANGE"
+			cc.Breaking = true
+		}
+		cc.Footers = append(cc.Footers, footer)
+	}
+	return cc
+}
@@ -0,0 +1,35 @@
+package analyzer
+
+import "testing"
+
+func TestParseConventionalCommit(t *testing.T) {
+	cc := ParseConventionalCommit("feat(api)!: drop v1 endpoints\n\nThe v1 API is gone.\n\nRefs: #123\nBREAKING CHANGE: clients must\n  move to v2\nReviewed-by: Alice\n")
+	if cc == nil {
+		t.Fatal("Expected a conventional commit")
+	}
+	if cc.Type != "feat" || cc.Scope != "api" || !cc.Breaking || cc.Description != "drop v1 endpoints" {
+		t.Errorf("Unexpected header: %+v", cc)
+	}
+	if len(cc.Footers) != 3 {
+		t.Fatalf("Expected 3 footers, got %+v", cc.Footers)
+	}
+	if cc.Footers[0] != (Footer{Token: "Refs", Value: "#123"}) {
+		t.Errorf("Unexpected first footer: %+v", cc.Footers[0])
+	}
+	if cc.Footers[1].Token != "BREAKING CHANGE" || cc.Footers[1].Value != "clients must\n  move to v2" {
+		t.Errorf("Unexpected breaking change footer: %+v", cc.Footers[1])
+	}
+
+	if cc := ParseConventionalCommit("Fix: typo\n\nBREAKING-CHANGE: renamed flag"); cc == nil || cc.Type != "fix" || !cc.Breaking {
+		t.Errorf("Expected a breaking fix from the footer, got %+v", cc)
+	}
+	if cc := ParseConventionalCommit("docs: readme"); cc == nil || cc.Scope != "" || cc.Breaking || len(cc.Footers) != 0 {
+		t.Errorf("Unexpected plain commit: %+v", cc)
+	}
+
+	for _, msg := range []string{"Update README", "feat:missing space", "feat(): ", "Merge branch 'main' into dev", ""} {
+		if cc := ParseConventionalCommit(msg); cc != nil {
+			t.Errorf("Expected %q not to parse, got %+v", msg, cc)
+		}
+	}
+}
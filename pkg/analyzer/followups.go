@@ -0,0 +1,65 @@
+package analyzer
+
+import (
+	"regexp"
+	"strings"
+)
+
+const (
+	FollowUpRevert = "revert"
+	FollowUpFixup  = "fixup"
+	FollowUpSquash = "squash"
+	// FollowUpFix is a small correction of earlier work, such as
+	// "fix typo" or "forgot to add file".
+	FollowUpFix = "fix"
+)
+
+// FollowUp marks a commit that undoes or patches up earlier work.
+type FollowUp struct {
+	Kind string
+	// Hash is the reverted commit from "This reverts commit <hash>".
+	Hash string
+	// Subject is the subject of the commit being reverted, fixed up or
+	// squashed, when the message names it.
+	Subject string
+}
+
+var (
+	revertHash    = regexp.MustCompile(`(?i)This reverts commit ([0-9a-f]{7,40})`)
+	revertSubject = regexp.MustCompile(`^Revert "(.*)"$`)
+	autosquash    = regexp.MustCompile(`^(fixup|squash|amend)! (.*)$`)
+	fixSubject    = regexp.MustCompile(`(?i)^(?:(?:fix(?:ed|es)?|correct(?:ed|s)?)\s+(?:a\s+|the\s+|some\s+)?(?:typos?|spelling|whitespace|indentation|lint(?:ing)?)\b|typos?\b|oops\b|forgot\b|missed\b|add(?:ed)? missing file)`)
+)
+
+// ClassifyFollowUp returns the follow-up kind of message, or nil for an
+// ordinary commit.
+func ClassifyFollowUp(message string) *FollowUp {
+	message = strings.TrimSpace(message)
+	subject := strings.TrimSpace(strings.SplitN(message, "\n", 2)[0])
+
+	if m := revertHash.FindStringSubmatch(message); m != nil {
+		f := &FollowUp{Kind: FollowUpRevert, Hash: strings.ToLower(m[1])}
+		if s := revertSubject.FindStringSubmatch(subject); s != nil {
+			f.Subject = s[1]
+		}
+		return f
+	}
+	if s := revertSubject.FindStringSubmatch(subject); s != nil {
+		return &FollowUp{Kind: FollowUpRevert, Subject: s[1]}
+	}
+	if m := autosquash.FindStringSubmatch(subject); m != nil {
+		kind := FollowUpFixup
+		if m[1] == "squash" {
+			kind = FollowUpSquash
+		}
+		return &FollowUp{Kind: kind, Subject: m[2]}
+	}
+	if fixSubject.MatchString(subject) {
+		return &FollowUp{Kind: FollowUpFix}
+	}
+	// Conventional Commits such as "fix: typo" or "docs: fix typo"
+	if cc := ParseConventionalCommit(message); cc != nil && fixSubject.MatchString(cc.Description) {
+		return &FollowUp{Kind: FollowUpFix}
+	}
+	return nil
+}
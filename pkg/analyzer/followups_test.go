@@ -0,0 +1,31 @@
+package analyzer
+
+import "testing"
+
+func TestClassifyFollowUp(t *testing.T) {
+	for _, tc := range []struct {
+		message string
+		want    *FollowUp
+	}{
+		{"Revert \"Add cache\"\n\nThis reverts commit 0123456789abcdef0123456789abcdef01234567.\n",
+			&FollowUp{Kind: FollowUpRevert, Hash: "0123456789abcdef0123456789abcdef01234567", Subject: "Add cache"}},
+		{"Revert \"Add cache\"", &FollowUp{Kind: FollowUpRevert, Subject: "Add cache"}},
+		{"fixup! Add cache", &FollowUp{Kind: FollowUpFixup, Subject: "Add cache"}},
+		{"squash! Add cache", &FollowUp{Kind: FollowUpSquash, Subject: "Add cache"}},
+		{"Fix typo in README", &FollowUp{Kind: FollowUpFix}},
+		{"oops, forgot the test", &FollowUp{Kind: FollowUpFix}},
+		{"fix: typo", &FollowUp{Kind: FollowUpFix}},
+		{"docs: fix typo in README", &FollowUp{Kind: FollowUpFix}},
+		{"style(cli): fix indentation", &FollowUp{Kind: FollowUpFix}},
+		{"chore!: forgot to add file", &FollowUp{Kind: FollowUpFix}},
+		{"Fix race in cache eviction", nil},
+		{"fix: race in cache eviction", nil},
+		{"docs: add typography guide", nil},
+		{"Add typography settings", nil},
+	} {
+		got := ClassifyFollowUp(tc.message)
+		if (got == nil) != (tc.want == nil) || (got != nil && *got != *tc.want) {
+			t.Errorf("ClassifyFollowUp(%q) = %+v, want %+v", tc.message, got, tc.want)
+		}
+	}
+}
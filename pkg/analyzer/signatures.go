@@ -0,0 +1,338 @@
+package analyzer
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	pgperrors "github.com/ProtonMail/go-crypto/openpgp/errors"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"golang.org/x/crypto/ssh"
+)
+
+// Signature statuses. Signed means a signature is present but no keyring or
+// allowed signers were given to verify it.
+const (
+	SignatureUnsigned   = "unsigned"
+	SignatureSigned     = "signed"
+	SignatureValid      = "valid"
+	SignatureInvalid    = "invalid"
+	SignatureUnknownKey = "unknown_key"
+)
+
+type Signature struct {
+	// Type is gpg, ssh or x509, empty for unsigned commits.
+	Type   string
+	Status string
+	// Signer is the key ID or principal of a verified signature.
+	Signer string
+}
+
+// SignatureVerifier checks GPG signatures against a keyring and SSH
+// signatures against an allowed signers file, in the format of git's
+// gpg.ssh.allowedSignersFile. A nil verifier only reports whether commits
+// are signed.
+type SignatureVerifier struct {
+	keyring openpgp.EntityList
+	signers []allowedSigner
+}
+
+// allowedSigner is a line of an allowed signers file: a pattern list of
+// principals, a key and the options restricting it.
+type allowedSigner struct {
+	principals string
+	key        ssh.PublicKey
+	// certAuthority accepts certificates signed by key instead of key itself.
+	certAuthority bool
+	// namespaces is a pattern list of the namespaces the key may sign in,
+	// empty for any.
+	namespaces  string
+	validAfter  time.Time
+	validBefore time.Time
+}
+
+// LoadSignatureVerifier reads a GPG keyring (armored or binary) and an
+// allowed signers file; either path may be empty. It returns nil when both
+// are.
+func LoadSignatureVerifier(keyringPath, allowedSignersPath string) (*SignatureVerifier, error) {
+	if keyringPath == "" && allowedSignersPath == "" {
+		return nil, nil
+	}
+	v := &SignatureVerifier{}
+	if keyringPath != "" {
+		data, err := os.ReadFile(keyringPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read keyring: %w", err)
+		}
+		v.keyring, err = openpgp.ReadArmoredKeyRing(bytes.NewReader(data))
+		if err != nil {
+			if v.keyring, err = openpgp.ReadKeyRing(bytes.NewReader(data)); err != nil {
+				return nil, fmt.Errorf("failed to parse keyring %s: %w", keyringPath, err)
+			}
+		}
+	}
+	if allowedSignersPath != "" {
+		signers, err := loadAllowedSigners(allowedSignersPath)
+		if err != nil {
+			return nil, err
+		}
+		v.signers = signers
+	}
+	return v, nil
+}
+
+func loadAllowedSigners(file string) ([]allowedSigner, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read allowed signers: %w", err)
+	}
+	defer f.Close()
+
+	var signers []allowedSigner
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		principals, rest, _ := strings.Cut(line, " ")
+		key, _, options, _, err := ssh.ParseAuthorizedKey([]byte(strings.TrimSpace(rest)))
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid allowed signer: %w", file, n, err)
+		}
+		signer := allowedSigner{principals: principals, key: key}
+		if err := signer.setOptions(options); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", file, n, err)
+		}
+		signers = append(signers, signer)
+	}
+	return signers, scanner.Err()
+}
+
+func (s *allowedSigner) setOptions(options []string) error {
+	for _, option := range options {
+		name, value, _ := strings.Cut(option, "=")
+		value = strings.Trim(value, `"`)
+		var err error
+		switch strings.ToLower(name) {
+		case "cert-authority":
+			s.certAuthority = true
+		case "namespaces":
+			s.namespaces = value
+		case "valid-after":
+			s.validAfter, err = parseSignerTime(value)
+		case "valid-before":
+			s.validBefore, err = parseSignerTime(value)
+		default:
+			return fmt.Errorf("unsupported allowed signer option %q", name)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseSignerTime parses the YYYYMMDD[HHMM[SS]][Z] times of valid-after and
+// valid-before, which are local unless they end in Z.
+func parseSignerTime(value string) (time.Time, error) {
+	loc := time.Local
+	if strings.HasSuffix(value, "Z") || strings.HasSuffix(value, "z") {
+		value, loc = value[:len(value)-1], time.UTC
+	}
+	for _, layout := range []string{"20060102", "200601021504", "20060102150405"} {
+		if len(value) == len(layout) {
+			if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+				return t, nil
+			}
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid allowed signer time %q, expected YYYYMMDD[HHMM[SS]][Z]", value)
+}
+
+// accepts reports whether the line trusts key at when, as
+// "ssh-keygen -Y find-principals" does for git verify-commit, and returns the
+// principal: email when the line lists it, as git reports the signer.
+func (s allowedSigner) accepts(key ssh.PublicKey, when time.Time, email string) (string, bool) {
+	if !s.validAfter.IsZero() && when.Before(s.validAfter) || !s.validBefore.IsZero() && when.After(s.validBefore) {
+		return "", false
+	}
+	if s.certAuthority {
+		cert, ok := key.(*ssh.Certificate)
+		if !ok || cert.CertType != ssh.UserCert || !bytes.Equal(cert.SignatureKey.Marshal(), s.key.Marshal()) {
+			return "", false
+		}
+		checker := &ssh.CertChecker{Clock: func() time.Time { return when }}
+		for _, principal := range cert.ValidPrincipals {
+			if matchPatternList(principal, s.principals) && checker.CheckCert(principal, cert) == nil {
+				return principal, true
+			}
+		}
+		return "", false
+	}
+	if !bytes.Equal(s.key.Marshal(), key.Marshal()) {
+		return "", false
+	}
+	if matchPatternList(email, s.principals) {
+		return email, true
+	}
+	return s.principals, true
+}
+
+// matchPatternList matches s against a comma separated list of wildcard
+// patterns, where a match of a pattern negated with "!" rejects s.
+func matchPatternList(s, list string) bool {
+	matched := false
+	for _, pattern := range strings.Split(list, ",") {
+		negated := strings.HasPrefix(pattern, "!")
+		if ok, _ := path.Match(strings.TrimPrefix(pattern, "!"), s); ok {
+			if negated {
+				return false
+			}
+			matched = true
+		}
+	}
+	return matched
+}
+
+// Verify returns the signature of c and, when the verifier can check it,
+// whether it is valid.
+func (v *SignatureVerifier) Verify(c *object.Commit) Signature {
+	armored := strings.TrimSpace(c.PGPSignature)
+	switch {
+	case armored == "":
+		return Signature{Status: SignatureUnsigned}
+	case strings.HasPrefix(armored, "-----BEGIN SSH SIGNATURE-----"):
+		if v == nil || v.signers == nil {
+			return Signature{Type: "ssh", Status: SignatureSigned}
+		}
+		return v.verifySSH(c, armored)
+	case strings.HasPrefix(armored, "-----BEGIN PGP SIGNATURE-----"):
+		if v == nil || v.keyring == nil {
+			return Signature{Type: "gpg", Status: SignatureSigned}
+		}
+		return v.verifyGPG(c, armored)
+	default:
+		return Signature{Type: "x509", Status: SignatureSigned}
+	}
+}
+
+func signedPayload(c *object.Commit) ([]byte, error) {
+	encoded := &plumbing.MemoryObject{}
+	if err := c.EncodeWithoutSignature(encoded); err != nil {
+		return nil, err
+	}
+	r, err := encoded.Reader()
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(r)
+}
+
+func (v *SignatureVerifier) verifyGPG(c *object.Commit, armored string) Signature {
+	sig := Signature{Type: "gpg", Status: SignatureInvalid}
+	payload, err := signedPayload(c)
+	if err != nil {
+		return sig
+	}
+	entity, err := openpgp.CheckArmoredDetachedSignature(v.keyring, bytes.NewReader(payload), strings.NewReader(armored), nil)
+	switch {
+	case errors.Is(err, pgperrors.ErrUnknownIssuer):
+		sig.Status = SignatureUnknownKey
+	case err == nil:
+		sig.Status = SignatureValid
+		sig.Signer = entity.PrimaryKey.KeyIdString()
+	}
+	return sig
+}
+
+type sshSig struct {
+	Version       uint32
+	PublicKey     []byte
+	Namespace     string
+	Reserved      string
+	HashAlgorithm string
+	Signature     []byte
+}
+
+func (v *SignatureVerifier) verifySSH(c *object.Commit, armored string) Signature {
+	sig := Signature{Type: "ssh", Status: SignatureInvalid}
+	body := strings.TrimSpace(armored)
+	body = strings.TrimPrefix(body, "-----BEGIN SSH SIGNATURE-----")
+	body = strings.TrimSuffix(body, "-----END SSH SIGNATURE-----")
+	blob, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(body), ""))
+	if err != nil || !bytes.HasPrefix(blob, []byte("SSHSIG")) {
+		return sig
+	}
+	var parsed sshSig
+	if err := ssh.Unmarshal(blob[6:], &parsed); err != nil || parsed.Namespace != "git" {
+		return sig
+	}
+	key, err := ssh.ParsePublicKey(parsed.PublicKey)
+	if err != nil {
+		return sig
+	}
+	var signature ssh.Signature
+	if err := ssh.Unmarshal(parsed.Signature, &signature); err != nil {
+		return sig
+	}
+
+	payload, err := signedPayload(c)
+	if err != nil {
+		return sig
+	}
+	var digest []byte
+	switch parsed.HashAlgorithm {
+	case "sha256":
+		sum := sha256.Sum256(payload)
+		digest = sum[:]
+	case "sha512":
+		sum := sha512.Sum512(payload)
+		digest = sum[:]
+	default:
+		return sig
+	}
+	signed := append([]byte("SSHSIG"), ssh.Marshal(struct {
+		Namespace     string
+		Reserved      string
+		HashAlgorithm string
+		Hash          []byte
+	}{parsed.Namespace, parsed.Reserved, parsed.HashAlgorithm, digest})...)
+	if err := key.Verify(signed, &signature); err != nil {
+		return sig
+	}
+
+	// git finds the principal at the commit time, then rejects signatures
+	// in namespaces the key is not allowed to sign in as bad
+	sig.Status = SignatureUnknownKey
+	for _, signer := range v.signers {
+		principal, ok := signer.accepts(key, c.Committer.When, c.Committer.Email)
+		if !ok {
+			continue
+		}
+		if signer.namespaces != "" && !matchPatternList(parsed.Namespace, signer.namespaces) {
+			sig.Status = SignatureInvalid
+			return sig
+		}
+		sig.Status = SignatureValid
+		sig.Signer = principal
+		return sig
+	}
+	return sig
+}
+
+// SetSignatureVerifier sets the verifier used to fill CommitInfo.Signature.
+func (ga *GitAnalyzer) SetSignatureVerifier(v *SignatureVerifier) {
+	ga.verifier = v
+}
@@ -0,0 +1,221 @@
+package analyzer
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"golang.org/x/crypto/ssh"
+)
+
+func testCommit(message string) *object.Commit {
+	who := object.Signature{Name: "Alice", Email: "alice@example.com", When: time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)}
+	return &object.Commit{Author: who, Committer: who, Message: message}
+}
+
+// sshSign signs c the way "git commit -S" does with gpg.format=ssh.
+func sshSign(t *testing.T, c *object.Commit, signer ssh.Signer) {
+	payload, err := signedPayload(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := sha512.Sum512(payload)
+	signed := append([]byte("SSHSIG"), ssh.Marshal(struct {
+		Namespace, Reserved, HashAlgorithm string
+		Hash                               []byte
+	}{"git", "", "sha512", digest[:]})...)
+	signature, err := signer.Sign(rand.Reader, signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blob := append([]byte("SSHSIG"), ssh.Marshal(sshSig{
+		Version:       1,
+		PublicKey:     signer.PublicKey().Marshal(),
+		Namespace:     "git",
+		HashAlgorithm: "sha512",
+		Signature:     ssh.Marshal(signature),
+	})...)
+	c.PGPSignature = "-----BEGIN SSH SIGNATURE-----\n" + base64.StdEncoding.EncodeToString(blob) + "\n-----END SSH SIGNATURE-----\n"
+}
+
+func TestVerifySSHSignature(t *testing.T) {
+	_, key, _ := ed25519.GenerateKey(rand.Reader)
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, otherKey, _ := ed25519.GenerateKey(rand.Reader)
+	other, _ := ssh.NewSignerFromKey(otherKey)
+
+	allowed := filepath.Join(t.TempDir(), "allowed_signers")
+	line := "*@example.com " + string(ssh.MarshalAuthorizedKey(signer.PublicKey()))
+	if err := os.WriteFile(allowed, []byte("# team keys\n"+line), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	verifier, err := LoadSignatureVerifier("", allowed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	valid := testCommit("Add cache\n")
+	sshSign(t, valid, signer)
+	if got := verifier.Verify(valid); got.Status != SignatureValid || got.Type != "ssh" || got.Signer != "alice@example.com" {
+		t.Errorf("valid signature = %+v", got)
+	}
+	if got := (*SignatureVerifier)(nil).Verify(valid); got.Status != SignatureSigned {
+		t.Errorf("without verifier = %+v, want signed", got)
+	}
+
+	tampered := testCommit("Add cache\n")
+	sshSign(t, tampered, signer)
+	tampered.Message = "Remove cache\n"
+	if got := verifier.Verify(tampered); got.Status != SignatureInvalid {
+		t.Errorf("tampered commit = %+v, want invalid", got)
+	}
+
+	stranger := testCommit("Add cache\n")
+	sshSign(t, stranger, other)
+	if got := verifier.Verify(stranger); got.Status != SignatureUnknownKey {
+		t.Errorf("unknown key = %+v, want unknown_key", got)
+	}
+
+	if got := verifier.Verify(testCommit("Add cache\n")); got.Status != SignatureUnsigned || got.Type != "" {
+		t.Errorf("unsigned commit = %+v", got)
+	}
+}
+
+func TestAllowedSignerOptions(t *testing.T) {
+	newSigner := func() ssh.Signer {
+		_, key, _ := ed25519.GenerateKey(rand.Reader)
+		signer, err := ssh.NewSignerFromKey(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signer
+	}
+	authorized := func(signer ssh.Signer) string {
+		return strings.TrimSpace(string(ssh.MarshalAuthorizedKey(signer.PublicKey())))
+	}
+	verify := func(line string, signer ssh.Signer) Signature {
+		allowed := filepath.Join(t.TempDir(), "allowed_signers")
+		if err := os.WriteFile(allowed, []byte(line+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		verifier, err := LoadSignatureVerifier("", allowed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c := testCommit("Add cache\n") // committed 2024-06-01 12:00 UTC
+		sshSign(t, c, signer)
+		return verifier.Verify(c)
+	}
+	key := newSigner()
+
+	ca := newSigner()
+	cert := &ssh.Certificate{
+		Key:             key.PublicKey(),
+		CertType:        ssh.UserCert,
+		ValidPrincipals: []string{"alice@example.com"},
+		ValidBefore:     ssh.CertTimeInfinity,
+	}
+	if err := cert.SignCert(rand.Reader, ca); err != nil {
+		t.Fatal(err)
+	}
+	certSigner, _ := ssh.NewCertSigner(cert, key)
+	expiredCert := *cert
+	expiredCert.ValidBefore = uint64(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).Unix())
+	if err := expiredCert.SignCert(rand.Reader, ca); err != nil {
+		t.Fatal(err)
+	}
+	expiredSigner, _ := ssh.NewCertSigner(&expiredCert, key)
+
+	for _, tc := range []struct {
+		name, line string
+		signer     ssh.Signer
+		status     string
+		principal  string
+	}{
+		{"git namespace", `*@example.com namespaces="git,file" ` + authorized(key), key, SignatureValid, "alice@example.com"},
+		{"other namespace", `*@example.com namespaces="file" ` + authorized(key), key, SignatureInvalid, ""},
+		{"negated namespace", `*@example.com namespaces="*,!git" ` + authorized(key), key, SignatureInvalid, ""},
+		{"expired", `*@example.com valid-before="20240101" ` + authorized(key), key, SignatureUnknownKey, ""},
+		{"not yet valid", `*@example.com valid-after="20240601130000Z" ` + authorized(key), key, SignatureUnknownKey, ""},
+		{"within validity", `*@example.com valid-after="20240101",valid-before="202406011300Z" ` + authorized(key), key, SignatureValid, "alice@example.com"},
+		{"principal other than the committer", `release@example.com ` + authorized(key), key, SignatureValid, "release@example.com"},
+		{"certificate", `*@example.com cert-authority ` + authorized(ca), certSigner, SignatureValid, "alice@example.com"},
+		{"certificate for another principal", `bob@example.com cert-authority ` + authorized(ca), certSigner, SignatureUnknownKey, ""},
+		{"expired certificate", `*@example.com cert-authority ` + authorized(ca), expiredSigner, SignatureUnknownKey, ""},
+		{"key of a certificate authority", `*@example.com ` + authorized(ca), certSigner, SignatureUnknownKey, ""},
+	} {
+		if got := verify(tc.line, tc.signer); got.Status != tc.status || got.Signer != tc.principal {
+			t.Errorf("%s: %+v, want %s by %q", tc.name, got, tc.status, tc.principal)
+		}
+	}
+
+	allowed := filepath.Join(t.TempDir(), "allowed_signers")
+	os.WriteFile(allowed, []byte(`* no-touch-required `+authorized(key)+"\n"), 0o644)
+	if _, err := LoadSignatureVerifier("", allowed); err == nil {
+		t.Error("expected an error for an unsupported option")
+	}
+}
+
+func TestVerifyGPGSignature(t *testing.T) {
+	entity, err := openpgp.NewEntity("Alice", "", "alice@example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stranger, _ := openpgp.NewEntity("Mallory", "", "mallory@example.com", nil)
+
+	var keyring bytes.Buffer
+	w, _ := armor.Encode(&keyring, openpgp.PublicKeyType, nil)
+	if err := entity.Serialize(w); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+	keyringPath := filepath.Join(t.TempDir(), "keyring.asc")
+	if err := os.WriteFile(keyringPath, keyring.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	verifier, err := LoadSignatureVerifier(keyringPath, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sign := func(c *object.Commit, by *openpgp.Entity) {
+		payload, _ := signedPayload(c)
+		var sig strings.Builder
+		if err := openpgp.ArmoredDetachSign(&sig, by, bytes.NewReader(payload), nil); err != nil {
+			t.Fatal(err)
+		}
+		c.PGPSignature = sig.String()
+	}
+
+	valid := testCommit("Add cache\n")
+	sign(valid, entity)
+	if got := verifier.Verify(valid); got.Status != SignatureValid || got.Signer != entity.PrimaryKey.KeyIdString() {
+		t.Errorf("valid signature = %+v", got)
+	}
+
+	tampered := testCommit("Add cache\n")
+	sign(tampered, entity)
+	tampered.Message = "Remove cache\n"
+	if got := verifier.Verify(tampered); got.Status != SignatureInvalid {
+		t.Errorf("tampered commit = %+v, want invalid", got)
+	}
+
+	unknown := testCommit("Add cache\n")
+	sign(unknown, stranger)
+	if got := verifier.Verify(unknown); got.Status != SignatureUnknownKey {
+		t.Errorf("unknown key = %+v, want unknown_key", got)
+	}
+}
@@ -0,0 +1,144 @@
+package analyzer
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage/filesystem"
+)
+
+// RepoStatus is the state of a repository's working tree and current branch.
+type RepoStatus struct {
+	// Branch is the current branch, or the short commit hash when HEAD is
+	// detached.
+	Branch   string `json:"branch"`
+	Detached bool   `json:"detached"`
+	// Modified counts tracked files with staged or unstaged changes.
+	Modified  int `json:"modified"`
+	Untracked int `json:"untracked"`
+	Stashes   int `json:"stashes"`
+	// Upstream is the branch the current branch tracks, empty when none.
+	Upstream string `json:"upstream"`
+	Ahead    int    `json:"ahead"`
+	Behind   int    `json:"behind"`
+}
+
+// Dirty reports uncommitted changes or untracked files.
+func (s *RepoStatus) Dirty() bool {
+	return s.Modified > 0 || s.Untracked > 0
+}
+
+// HasLocalWork reports work that exists only in this clone: uncommitted
+// changes, commits not pushed upstream or stashes.
+func (s *RepoStatus) HasLocalWork() bool {
+	return s.Dirty() || s.Ahead > 0 || s.Stashes > 0
+}
+
+// GetStatus reports the current branch, the working tree changes, the stash
+// count and how far the branch is from its upstream.
+func (ga *GitAnalyzer) GetStatus() (*RepoStatus, error) {
+	repo, err := git.PlainOpen(ga.repoPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository: %w", err)
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get HEAD: %w", err)
+	}
+
+	status := &RepoStatus{}
+	if head.Name().IsBranch() {
+		status.Branch = head.Name().Short()
+	} else {
+		status.Branch = head.Hash().String()[:7]
+		status.Detached = true
+	}
+
+	if wt, err := repo.Worktree(); err == nil {
+		changes, err := wt.Status()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get worktree status: %w", err)
+		}
+		for _, file := range changes {
+			switch {
+			case file.Worktree == git.Untracked:
+				status.Untracked++
+			case file.Worktree != git.Unmodified || file.Staging != git.Unmodified:
+				status.Modified++
+			}
+		}
+	}
+
+	if status.Stashes, err = countStashes(repo); err != nil {
+		return nil, err
+	}
+
+	if !status.Detached {
+		if err := upstreamDistance(repo, head, status); err != nil {
+			return nil, err
+		}
+	}
+	return status, nil
+}
+
+// countStashes counts the entries of the stash reflog, which go-git does not
+// expose.
+func countStashes(repo *git.Repository) (int, error) {
+	storage, ok := repo.Storer.(*filesystem.Storage)
+	if !ok {
+		return 0, nil
+	}
+	f, err := storage.Filesystem().Open("logs/refs/stash")
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read stash log: %w", err)
+	}
+	defer f.Close()
+	n := 0
+	for scanner := bufio.NewScanner(f); scanner.Scan(); {
+		n++
+	}
+	return n, nil
+}
+
+// upstreamDistance fills the upstream of the current branch from the
+// repository config and counts the commits ahead of and behind it.
+func upstreamDistance(repo *git.Repository, head *plumbing.Reference, status *RepoStatus) error {
+	cfg, err := repo.Config()
+	if err != nil {
+		return fmt.Errorf("failed to read config: %w", err)
+	}
+	branch, ok := cfg.Branches[status.Branch]
+	if !ok || branch.Merge == "" {
+		return nil
+	}
+	upstream := branch.Merge
+	if branch.Remote != "" && branch.Remote != "." {
+		upstream = plumbing.NewRemoteReferenceName(branch.Remote, branch.Merge.Short())
+	}
+	ref, err := repo.Reference(upstream, true)
+	if err != nil {
+		// the upstream is configured but not fetched yet, or gone
+		status.Upstream = upstream.Short()
+		return nil
+	}
+	status.Upstream = ref.Name().Short()
+
+	graph := newCommitGraph(repo)
+	ours, err := graph.ancestors(head.Hash())
+	if err != nil {
+		return err
+	}
+	theirs, err := graph.ancestors(ref.Hash())
+	if err != nil {
+		return err
+	}
+	status.Ahead, status.Behind = aheadBehind(ours, theirs)
+	return nil
+}
@@ -0,0 +1,66 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func TestGetStatus(t *testing.T) {
+	dir := t.TempDir()
+	repo, err := git.PlainInitWithOptions(dir, &git.PlainInitOptions{
+		InitOptions: git.InitOptions{DefaultBranch: plumbing.Main},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wt, _ := repo.Worktree()
+	write := func(file, content string) {
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	commit := func(file string) plumbing.Hash {
+		write(file, file)
+		wt.Add(file)
+		who := &object.Signature{Name: "Alice", When: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+		h, err := wt.Commit("Add "+file, &git.CommitOptions{Author: who})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return h
+	}
+
+	base := commit("a")
+	status, err := NewGitAnalyzer(dir).GetStatus()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *status != (RepoStatus{Branch: "main"}) || status.HasLocalWork() {
+		t.Errorf("clean status = %+v", status)
+	}
+
+	// track a local "published" branch left one commit behind
+	repo.Storer.SetReference(plumbing.NewHashReference(plumbing.NewBranchReferenceName("published"), base))
+	repo.CreateBranch(&config.Branch{Name: "main", Remote: ".", Merge: plumbing.NewBranchReferenceName("published")})
+	commit("b")
+	write("a", "changed")
+	write("new", "new")
+	os.MkdirAll(filepath.Join(dir, ".git", "logs", "refs"), 0o755)
+	os.WriteFile(filepath.Join(dir, ".git", "logs", "refs", "stash"), []byte("0 1 A <a> 0 +0000\tWIP\n0 2 A <a> 0 +0000\tWIP\n"), 0o644)
+
+	status, err = NewGitAnalyzer(dir).GetStatus()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := RepoStatus{Branch: "main", Modified: 1, Untracked: 1, Stashes: 2, Upstream: "published", Ahead: 1}
+	if *status != want || !status.Dirty() {
+		t.Errorf("status = %+v, want %+v", *status, want)
+	}
+}
@@ -0,0 +1,65 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// Tag is a tag pointing at a commit. Date is the tagger date of an annotated
+// tag and the committer date of a lightweight one.
+type Tag struct {
+	Name string
+	Date time.Time
+}
+
+// tagsByCommit maps commit hashes to the tags pointing at them. Tags of
+// trees, blobs or other tags are skipped.
+func tagsByCommit(repo *git.Repository) (map[string][]Tag, error) {
+	iter, err := repo.Tags()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+	tags := make(map[string][]Tag)
+	err = iter.ForEach(func(ref *plumbing.Reference) error {
+		tag := Tag{Name: ref.Name().Short()}
+		hash := ref.Hash()
+		if annotated, err := repo.TagObject(hash); err == nil {
+			commit, err := annotated.Commit()
+			if err != nil {
+				return nil
+			}
+			hash, tag.Date = commit.Hash, annotated.Tagger.When
+		} else {
+			commit, err := repo.CommitObject(hash)
+			if err != nil {
+				return nil
+			}
+			tag.Date = commit.Committer.When
+		}
+		tags[hash.String()] = append(tags[hash.String()], tag)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate tags: %w", err)
+	}
+	for _, t := range tags {
+		sort.Slice(t, func(i, j int) bool { return t[i].Name < t[j].Name })
+	}
+	return tags, nil
+}
+
+// attachTags fills CommitInfo.Tags from the repository's tags.
+func attachTags(repo *git.Repository, commits []CommitInfo) error {
+	tags, err := tagsByCommit(repo)
+	if err != nil {
+		return err
+	}
+	for i := range commits {
+		commits[i].Tags = tags[commits[i].Hash]
+	}
+	return nil
+}
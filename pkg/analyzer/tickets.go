@@ -0,0 +1,78 @@
+package analyzer
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DefaultTicketPatterns match JIRA style keys (PROJ-123), GitHub style
+// issue numbers (#456) and the value of Fixes/Closes/Resolves/Refs lines below
+// the subject in any tracker's form (Fixes: 1234, Refs: https://bugs/1234), as
+// long as it has a digit, so that Conventional Commit subjects such as
+// "fix: handle nil" and prose such as "Refs: the old approach" are not taken
+// for references.
+var DefaultTicketPatterns = []string{
+	`\b([A-Z][A-Z0-9]+-\d+)\b`,
+	`(?:^|[^\w&/])(#\d+)\b`,
+	`\n(?i:fix(?:es|ed)?|close[sd]?|resolve[sd]?|refs?):[ \t]*([^\s,;]*\d[^\s,;]*)`,
+}
+
+// notTicketKeys are the keys of well-known standards and algorithms that look
+// like JIRA keys, as in UTF-8 or SHA-256.
+var notTicketKeys = map[string]bool{
+	"AES": true, "CVE": true, "HTTP": true, "ISO": true, "MD": true, "RFC": true,
+	"SHA": true, "TLS": true, "UTF": true, "UCS": true, "X": true,
+}
+
+// commitHash matches abbreviated commit hashes, as in the kernel's
+// "Fixes: 54a4f0239f2e (...)" footer, which name commits rather than tickets.
+var commitHash = regexp.MustCompile(`^[0-9a-f]*[a-f][0-9a-f]*$`)
+
+// CompileTicketPatterns compiles ticket patterns. A pattern's first capture
+// group is the ticket, or the whole match when it has none.
+func CompileTicketPatterns(patterns []string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ticket pattern %q: %w", p, err)
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+var defaultTicketPatterns, _ = CompileTicketPatterns(DefaultTicketPatterns)
+
+// ExtractTickets returns the distinct tickets referenced in message, in the
+// order they first appear per pattern.
+func ExtractTickets(message string, patterns []*regexp.Regexp) []string {
+	var tickets []string
+	seen := make(map[string]bool)
+	for _, re := range patterns {
+		for _, m := range re.FindAllStringSubmatch(message, -1) {
+			ticket := m[0]
+			if len(m) > 1 {
+				ticket = m[1]
+			}
+			ticket = strings.TrimRight(strings.TrimSpace(ticket), ".,;:)")
+			if key, _, ok := strings.Cut(ticket, "-"); ok && notTicketKeys[key] {
+				continue
+			}
+			if len(ticket) >= 7 && commitHash.MatchString(ticket) {
+				continue
+			}
+			if ticket != "" && !seen[ticket] {
+				seen[ticket] = true
+				tickets = append(tickets, ticket)
+			}
+		}
+	}
+	return tickets
+}
+
+// SetTicketPatterns replaces the patterns used to fill CommitInfo.Tickets.
+func (ga *GitAnalyzer) SetTicketPatterns(patterns []*regexp.Regexp) {
+	ga.ticketPatterns = patterns
+}
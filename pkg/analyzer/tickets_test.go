@@ -0,0 +1,46 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractTickets(t *testing.T) {
+	message := "PAY-12: retry failed charges (#456)\n\nSee also PAY-12 and https://example.com/x#789.\n\nFixes: OPS-7\nRefs: #456\n"
+	got := ExtractTickets(message, defaultTicketPatterns)
+	want := []string{"PAY-12", "OPS-7", "#456"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ExtractTickets() = %v, want %v", got, want)
+	}
+
+	for _, message := range []string{
+		"fix: handle nil pointer",
+		"Refs: the old approach",
+		"feat(api): support UTF-8 and SHA-256 digests\n\nCloses: later",
+	} {
+		if got := ExtractTickets(message, defaultTicketPatterns); len(got) != 0 {
+			t.Errorf("ExtractTickets(%q) = %v, want none", message, got)
+		}
+	}
+	for message, want := range map[string][]string{
+		"fix: retry\n\nfixes: pay-9\nResolves: DATA-3":                          {"DATA-3", "pay-9"},
+		"Retry charges\n\nFixes: 1234":                                          {"1234"},
+		"Retry charges\n\nCloses: https://bugs.example.com/show_bug.cgi?id=77.": {"https://bugs.example.com/show_bug.cgi?id=77"},
+		"Retry charges\n\nFixes: 54a4f0239f2e (\"Add charges\")":                nil,
+	} {
+		if got := ExtractTickets(message, defaultTicketPatterns); !reflect.DeepEqual(got, want) {
+			t.Errorf("ExtractTickets(%q) = %v, want %v", message, got, want)
+		}
+	}
+
+	custom, err := CompileTicketPatterns([]string{`\bCASE\d+\b`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ExtractTickets("handle CASE42, not PAY-12", custom); !reflect.DeepEqual(got, []string{"CASE42"}) {
+		t.Errorf("Expected only the custom pattern to match, got %v", got)
+	}
+	if _, err := CompileTicketPatterns([]string{"("}); err == nil {
+		t.Errorf("Expected an error for an invalid pattern")
+	}
+}
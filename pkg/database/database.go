@@ -0,0 +1,428 @@
+// Package database exports analysed repositories into a SQLite database so
+// that their history can be queried with SQL.
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"git-watcher/pkg/analyzer"
+
+	_ "modernc.org/sqlite"
+)
+
+// schemaVersion is stored in PRAGMA user_version; bump it when Schema
+// changes incompatibly.
+const schemaVersion = 1
+
+// timeFormat is SQLite's native date and time format.
+const timeFormat = "2006-01-02 15:04:05"
+
+// Schema is the layout of the database. Timestamps are UTC in SQLite's
+// "YYYY-MM-DD HH:MM:SS" format, so that date() and strftime() work on them
+// directly. The comments are kept by SQLite and shown by the sqlite3 shell's
+// .schema command.
+const Schema = `
+-- One row per scanned repository.
+CREATE TABLE IF NOT EXISTS repositories (
+	id            INTEGER PRIMARY KEY,
+	path          TEXT NOT NULL UNIQUE, -- absolute path of the working tree
+	name          TEXT NOT NULL,        -- base name of path
+	total_commits INTEGER NOT NULL DEFAULT 0,
+	exported_at   TEXT NOT NULL         -- time of the latest export
+);
+
+-- Commit authors, one row per distinct name and email.
+CREATE TABLE IF NOT EXISTS authors (
+	id    INTEGER PRIMARY KEY,
+	name  TEXT NOT NULL,
+	email TEXT NOT NULL,
+	UNIQUE (name, email)
+);
+CREATE INDEX IF NOT EXISTS authors_email ON authors (email);
+
+-- Commits of each repository. hour and weekday (0 = Sunday) are in the
+-- author's recorded time zone, utc_offset is that zone in minutes.
+CREATE TABLE IF NOT EXISTS commits (
+	repository_id    INTEGER NOT NULL REFERENCES repositories (id),
+	hash             TEXT NOT NULL,
+	author_id        INTEGER NOT NULL REFERENCES authors (id),
+	authored_at      TEXT NOT NULL,
+	utc_offset       INTEGER NOT NULL,
+	hour             INTEGER NOT NULL,
+	weekday          INTEGER NOT NULL,
+	subject          TEXT NOT NULL,
+	message          TEXT NOT NULL,
+	line_count       INTEGER NOT NULL, -- lines added plus lines deleted
+	parents          INTEGER NOT NULL, -- number of parents, 2 or more for merges
+	conventional     TEXT,             -- Conventional Commits type, NULL if none
+	signature_status TEXT NOT NULL,    -- unsigned, signed, valid, invalid or unknown_key
+	PRIMARY KEY (repository_id, hash)
+);
+CREATE INDEX IF NOT EXISTS commits_author ON commits (author_id);
+CREATE INDEX IF NOT EXISTS commits_authored_at ON commits (authored_at);
+
+-- Lines added and deleted per file and commit.
+CREATE TABLE IF NOT EXISTS file_changes (
+	repository_id INTEGER NOT NULL,
+	hash          TEXT NOT NULL,
+	path          TEXT NOT NULL,
+	additions     INTEGER NOT NULL,
+	deletions     INTEGER NOT NULL,
+	PRIMARY KEY (repository_id, hash, path),
+	FOREIGN KEY (repository_id, hash) REFERENCES commits (repository_id, hash)
+);
+CREATE INDEX IF NOT EXISTS file_changes_path ON file_changes (path);
+
+-- Issue tracker references found in commit messages.
+CREATE TABLE IF NOT EXISTS commit_tickets (
+	repository_id INTEGER NOT NULL,
+	hash          TEXT NOT NULL,
+	ticket        TEXT NOT NULL,
+	PRIMARY KEY (repository_id, hash, ticket),
+	FOREIGN KEY (repository_id, hash) REFERENCES commits (repository_id, hash)
+);
+CREATE INDEX IF NOT EXISTS commit_tickets_ticket ON commit_tickets (ticket);
+
+-- The statistics of the latest export as JSON, one row per statistic;
+-- query them with json_extract() or json_each().
+CREATE TABLE IF NOT EXISTS statistics (
+	repository_id INTEGER NOT NULL REFERENCES repositories (id),
+	name          TEXT NOT NULL, -- statistic name as in the JSON output
+	value         TEXT NOT NULL,
+	PRIMARY KEY (repository_id, name)
+);
+
+-- Commits with their repository and author, for ad-hoc queries.
+CREATE VIEW IF NOT EXISTS commit_details AS
+SELECT r.path AS repository, c.hash, a.name AS author, a.email, c.authored_at,
+	c.hour, c.weekday, c.subject, c.line_count, c.parents, c.conventional, c.signature_status
+FROM commits c
+JOIN repositories r ON r.id = c.repository_id
+JOIN authors a ON a.id = c.author_id;
+`
+
+// DB is an open export database.
+type DB struct {
+	db *sql.DB
+}
+
+// ExportResult counts the commits an export added to, updated in and
+// removed from a repository.
+type ExportResult struct {
+	Added   int
+	Updated int
+	Removed int
+}
+
+// Open opens the database at path, creating it and its schema if needed.
+func Open(path string) (*DB, error) {
+	uri, err := fileURI(path, "_pragma=foreign_keys(1)")
+	if err != nil {
+		return nil, err
+	}
+	db, err := sql.Open("sqlite", uri)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	var version int
+	if err := db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to open database %s: %w", path, err)
+	}
+	if version > schemaVersion {
+		db.Close()
+		return nil, fmt.Errorf("database %s has schema version %d, this version of git-watcher supports %d", path, version, schemaVersion)
+	}
+	if _, err := db.Exec(Schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create schema: %w", err)
+	}
+	if _, err := db.Exec(fmt.Sprintf("PRAGMA user_version = %d", schemaVersion)); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to set schema version: %w", err)
+	}
+	return &DB{db: db}, nil
+}
+
+// OpenReadOnly opens an existing database for queries. SQLite itself opens
+// it read-only, so no statement, however crafted, can modify it.
+func OpenReadOnly(path string) (*DB, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	uri, err := fileURI(path, "mode=ro")
+	if err != nil {
+		return nil, err
+	}
+	db, err := sql.Open("sqlite", uri)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	var version int
+	if err := db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to open database %s: %w", path, err)
+	}
+	if version == 0 || version > schemaVersion {
+		db.Close()
+		return nil, fmt.Errorf("database %s has schema version %d, this version of git-watcher reads %d", path, version, schemaVersion)
+	}
+	return &DB{db: db}, nil
+}
+
+// fileURI returns the SQLite URI of path, so that file names may contain
+// "?" or "#" and query can set open parameters.
+func fileURI(path, query string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve path: %w", err)
+	}
+	abs = filepath.ToSlash(abs)
+	if !strings.HasPrefix(abs, "/") {
+		abs = "/" + abs
+	}
+	uri := url.URL{Scheme: "file", Path: abs, RawQuery: query}
+	return uri.String(), nil
+}
+
+func (d *DB) Close() error {
+	return d.db.Close()
+}
+
+// ExportRepository writes the commits of repo and its statistics. New
+// commits are added and those no longer in commits, after a rewrite of
+// history, are removed. Commits already in the database keep their row, but
+// the columns that depend on options rather than on the commit itself
+// (signature status, parents, tickets) are updated when they changed; the
+// statistics are replaced.
+func (d *DB) ExportRepository(repo string, commits []analyzer.CommitInfo, statistics map[string]interface{}) (ExportResult, error) {
+	var result ExportResult
+	tx, err := d.db.Begin()
+	if err != nil {
+		return result, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC().Format(timeFormat)
+	if _, err := tx.Exec(`INSERT INTO repositories (path, name, total_commits, exported_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT (path) DO UPDATE SET total_commits = excluded.total_commits, exported_at = excluded.exported_at`,
+		repo, filepath.Base(repo), len(commits), now); err != nil {
+		return result, fmt.Errorf("failed to write repository: %w", err)
+	}
+	var repoID int64
+	if err := tx.QueryRow("SELECT id FROM repositories WHERE path = ?", repo).Scan(&repoID); err != nil {
+		return result, fmt.Errorf("failed to read repository: %w", err)
+	}
+
+	stored, err := storedCommits(tx, repoID)
+	if err != nil {
+		return result, err
+	}
+	current := make(map[string]bool, len(commits))
+	authors := make(map[[2]string]int64)
+	for _, commit := range commits {
+		current[commit.Hash] = true
+		if old, ok := stored[commit.Hash]; ok {
+			if derivedOf(commit) != old {
+				if err := updateCommit(tx, repoID, commit); err != nil {
+					return result, err
+				}
+				result.Updated++
+			}
+			continue
+		}
+		key := [2]string{commit.Author, commit.Email}
+		authorID, ok := authors[key]
+		if !ok {
+			if authorID, err = author(tx, commit.Author, commit.Email); err != nil {
+				return result, err
+			}
+			authors[key] = authorID
+		}
+		if err := insertCommit(tx, repoID, authorID, commit); err != nil {
+			return result, err
+		}
+		result.Added++
+	}
+
+	var removed []string
+	for hash := range stored {
+		if !current[hash] {
+			removed = append(removed, hash)
+		}
+	}
+	sort.Strings(removed)
+	for _, hash := range removed {
+		for _, table := range []string{"file_changes", "commit_tickets", "commits"} {
+			if _, err := tx.Exec("DELETE FROM "+table+" WHERE repository_id = ? AND hash = ?", repoID, hash); err != nil {
+				return result, fmt.Errorf("failed to remove commit %s: %w", hash, err)
+			}
+		}
+		result.Removed++
+	}
+
+	if _, err := tx.Exec("DELETE FROM statistics WHERE repository_id = ?", repoID); err != nil {
+		return result, fmt.Errorf("failed to clear statistics: %w", err)
+	}
+	for name, value := range statistics {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return result, fmt.Errorf("failed to encode statistic %s: %w", name, err)
+		}
+		if _, err := tx.Exec("INSERT INTO statistics (repository_id, name, value) VALUES (?, ?, ?)", repoID, name, string(data)); err != nil {
+			return result, fmt.Errorf("failed to write statistic %s: %w", name, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return result, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return result, nil
+}
+
+// derived are the columns of a stored commit that depend on the options of
+// the export rather than on the commit.
+type derived struct {
+	status  string
+	parents int
+	tickets string // sorted and joined by newlines
+}
+
+func derivedOf(commit analyzer.CommitInfo) derived {
+	status := commit.Signature.Status
+	if status == "" {
+		status = analyzer.SignatureUnsigned
+	}
+	tickets := append([]string(nil), commit.Tickets...)
+	sort.Strings(tickets)
+	return derived{status: status, parents: len(commit.Parents), tickets: strings.Join(tickets, "\n")}
+}
+
+func storedCommits(tx *sql.Tx, repoID int64) (map[string]derived, error) {
+	rows, err := tx.Query(`SELECT c.hash, c.signature_status, c.parents,
+		(SELECT group_concat(t.ticket, char(10)) FROM commit_tickets t WHERE t.repository_id = c.repository_id AND t.hash = c.hash)
+		FROM commits c WHERE c.repository_id = ?`, repoID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read commits: %w", err)
+	}
+	defer rows.Close()
+	commits := make(map[string]derived)
+	for rows.Next() {
+		var hash string
+		var d derived
+		var tickets sql.NullString
+		if err := rows.Scan(&hash, &d.status, &d.parents, &tickets); err != nil {
+			return nil, fmt.Errorf("failed to read commits: %w", err)
+		}
+		if tickets.Valid {
+			list := strings.Split(tickets.String, "\n")
+			sort.Strings(list)
+			d.tickets = strings.Join(list, "\n")
+		}
+		commits[hash] = d
+	}
+	return commits, rows.Err()
+}
+
+func updateCommit(tx *sql.Tx, repoID int64, commit analyzer.CommitInfo) error {
+	d := derivedOf(commit)
+	if _, err := tx.Exec("UPDATE commits SET signature_status = ?, parents = ? WHERE repository_id = ? AND hash = ?",
+		d.status, d.parents, repoID, commit.Hash); err != nil {
+		return fmt.Errorf("failed to update commit %s: %w", commit.Hash, err)
+	}
+	if _, err := tx.Exec("DELETE FROM commit_tickets WHERE repository_id = ? AND hash = ?", repoID, commit.Hash); err != nil {
+		return fmt.Errorf("failed to update tickets of commit %s: %w", commit.Hash, err)
+	}
+	return insertTickets(tx, repoID, commit)
+}
+
+func author(tx *sql.Tx, name, email string) (int64, error) {
+	if _, err := tx.Exec("INSERT OR IGNORE INTO authors (name, email) VALUES (?, ?)", name, email); err != nil {
+		return 0, fmt.Errorf("failed to write author %s: %w", name, err)
+	}
+	var id int64
+	if err := tx.QueryRow("SELECT id FROM authors WHERE name = ? AND email = ?", name, email).Scan(&id); err != nil {
+		return 0, fmt.Errorf("failed to read author %s: %w", name, err)
+	}
+	return id, nil
+}
+
+func insertCommit(tx *sql.Tx, repoID, authorID int64, commit analyzer.CommitInfo) error {
+	_, offset := commit.Date.Zone()
+	var conventional interface{}
+	if commit.Conventional != nil {
+		conventional = commit.Conventional.Type
+	}
+	d := derivedOf(commit)
+	if _, err := tx.Exec(`INSERT INTO commits (repository_id, hash, author_id, authored_at, utc_offset, hour, weekday,
+		subject, message, line_count, parents, conventional, signature_status) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		repoID, commit.Hash, authorID, commit.Date.UTC().Format(timeFormat), offset/60, commit.Date.Hour(), int(commit.Date.Weekday()),
+		strings.SplitN(strings.TrimSpace(commit.Message), "\n", 2)[0], commit.Message, commit.LineCount,
+		d.parents, conventional, d.status); err != nil {
+		return fmt.Errorf("failed to write commit %s: %w", commit.Hash, err)
+	}
+	for _, f := range commit.Files {
+		if _, err := tx.Exec("INSERT OR IGNORE INTO file_changes (repository_id, hash, path, additions, deletions) VALUES (?, ?, ?, ?, ?)",
+			repoID, commit.Hash, f.Path, f.Additions, f.Deletions); err != nil {
+			return fmt.Errorf("failed to write changes of commit %s: %w", commit.Hash, err)
+		}
+	}
+	return insertTickets(tx, repoID, commit)
+}
+
+func insertTickets(tx *sql.Tx, repoID int64, commit analyzer.CommitInfo) error {
+	for _, ticket := range commit.Tickets {
+		if _, err := tx.Exec("INSERT OR IGNORE INTO commit_tickets (repository_id, hash, ticket) VALUES (?, ?, ?)",
+			repoID, commit.Hash, ticket); err != nil {
+			return fmt.Errorf("failed to write tickets of commit %s: %w", commit.Hash, err)
+		}
+	}
+	return nil
+}
+
+// Query runs a statement and returns the column names and the rows, with
+// every value converted to text. Open the database with OpenReadOnly to
+// reject writes.
+func (d *DB) Query(query string) ([]string, [][]string, error) {
+	rows, err := d.db.Query(query)
+	if err != nil {
+		return nil, nil, fmt.Errorf("query failed: %w", err)
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, nil, fmt.Errorf("query failed: %w", err)
+	}
+	var result [][]string
+	for rows.Next() {
+		values := make([]sql.NullString, len(columns))
+		ptrs := make([]interface{}, len(columns))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return nil, nil, fmt.Errorf("query failed: %w", err)
+		}
+		row := make([]string, len(columns))
+		for i, v := range values {
+			if v.Valid {
+				row[i] = v.String
+			} else {
+				row[i] = "NULL"
+			}
+		}
+		result = append(result, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("query failed: %w", err)
+	}
+	return columns, result, nil
+}
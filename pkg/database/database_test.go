@@ -0,0 +1,130 @@
+package database
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"git-watcher/pkg/analyzer"
+)
+
+func TestExportRepository(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.db")
+	db, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	cst := time.FixedZone("CST", 8*60*60)
+	commits := []analyzer.CommitInfo{
+		{
+			Hash: "a1", Author: "Alice", Email: "alice@example.com", Date: time.Date(2024, 6, 1, 23, 30, 0, 0, cst),
+			Message: "feat: add login\n\nPAY-1", LineCount: 12, Tickets: []string{"PAY-1"},
+			Conventional: &analyzer.ConventionalCommit{Type: "feat"},
+			Files:        []analyzer.FileChange{{Path: "login.go", Additions: 10, Deletions: 2}},
+		},
+		{Hash: "b2", Author: "Bob", Email: "bob@example.com", Date: time.Date(2024, 6, 3, 10, 0, 0, 0, time.UTC), Message: "fix typo", Parents: []string{"a1"}},
+	}
+	statistics := map[string]interface{}{"commit_count_by_author": map[string]int{"Alice": 1, "Bob": 1}}
+	result, err := db.ExportRepository("/src/app", commits, statistics)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != (ExportResult{Added: 2}) {
+		t.Errorf("first export = %+v", result)
+	}
+
+	if _, rows, err := db.Query("PRAGMA foreign_keys"); err != nil || rows[0][0] != "1" {
+		t.Errorf("foreign_keys = %v, %v", rows, err)
+	}
+	columns, rows, err := db.Query("SELECT author, authored_at, hour, weekday, conventional, signature_status FROM commit_details ORDER BY hash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(columns) != 6 || len(rows) != 2 {
+		t.Fatalf("commit_details = %v %v", columns, rows)
+	}
+	if got := strings.Join(rows[0], "|"); got != "Alice|2024-06-01 15:30:00|23|6|feat|unsigned" {
+		t.Errorf("Alice's commit = %s", got)
+	}
+	if rows[1][4] != "NULL" {
+		t.Errorf("Bob's conventional type = %s", rows[1][4])
+	}
+	_, rows, err = db.Query("SELECT f.path, t.ticket, json_extract(s.value, '$.Bob') FROM file_changes f, commit_tickets t, statistics s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1 || strings.Join(rows[0], "|") != "login.go|PAY-1|1" {
+		t.Errorf("changes, tickets and statistics = %v", rows)
+	}
+
+	// b2 was rewritten as c3, a1 verified against a keyring and its
+	// tickets extracted with other patterns
+	commits[1].Hash = "c3"
+	commits[0].Signature.Status = analyzer.SignatureValid
+	commits[0].Tickets = []string{"#7"}
+	commits = append(commits, analyzer.CommitInfo{Hash: "d4", Author: "Alice", Email: "alice@example.com", Date: time.Date(2024, 6, 4, 9, 0, 0, 0, time.UTC)})
+	if result, err = db.ExportRepository("/src/app", commits, statistics); err != nil {
+		t.Fatal(err)
+	}
+	if result != (ExportResult{Added: 2, Updated: 1, Removed: 1}) {
+		t.Errorf("second export = %+v", result)
+	}
+	_, rows, err = db.Query("SELECT (SELECT group_concat(hash) FROM (SELECT hash FROM commits ORDER BY hash)), (SELECT count(*) FROM authors), (SELECT total_commits FROM repositories)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(rows[0], "|") != "a1,c3,d4|2|3" {
+		t.Errorf("after re-export = %v", rows[0])
+	}
+	_, rows, err = db.Query("SELECT c.signature_status, t.ticket FROM commits c JOIN commit_tickets t USING (repository_id, hash)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1 || strings.Join(rows[0], "|") != "valid|#7" {
+		t.Errorf("updated commit = %v", rows)
+	}
+	if result, err = db.ExportRepository("/src/app", commits, statistics); err != nil || result != (ExportResult{}) {
+		t.Errorf("unchanged export = %+v, %v", result, err)
+	}
+}
+
+func TestOpenReadOnly(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "history?.db")
+	db, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	commits := []analyzer.CommitInfo{{Hash: "a1", Author: "Alice", Date: time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)}}
+	if _, err := db.ExportRepository("/src/app", commits, nil); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	ro, err := OpenReadOnly(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ro.Close()
+	for _, query := range []string{
+		"DELETE FROM commits",
+		"PRAGMA query_only = OFF; DELETE FROM commits; SELECT 1",
+		"SELECT 1; DELETE FROM commits",
+	} {
+		ro.Query(query)
+	}
+	if _, rows, err := ro.Query("SELECT count(*) FROM commits"); err != nil || rows[0][0] != "1" {
+		t.Errorf("commits after writes = %v, %v", rows, err)
+	}
+
+	if _, err := OpenReadOnly(filepath.Join(dir, "missing.db")); err == nil {
+		t.Error("OpenReadOnly(missing) succeeded")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "missing.db")); !os.IsNotExist(err) {
+		t.Error("OpenReadOnly created the database")
+	}
+}
@@ -0,0 +1,273 @@
+package policy
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const dateFormat = "2006-01-02"
+
+type Holiday struct {
+	Date   string `json:"date"`
+	Name   string `json:"name"`
+	Region string `json:"region,omitempty"`
+}
+
+// Calendar is a set of public holidays keyed by calendar date. A nil
+// *Calendar has no holidays.
+type Calendar struct {
+	days map[string][]Holiday
+}
+
+func NewCalendar() *Calendar {
+	return &Calendar{days: make(map[string][]Holiday)}
+}
+
+func (c *Calendar) Add(h Holiday) {
+	for _, existing := range c.days[h.Date] {
+		if existing == h {
+			return
+		}
+	}
+	c.days[h.Date] = append(c.days[h.Date], h)
+}
+
+func (c *Calendar) Merge(other *Calendar) {
+	if other == nil {
+		return
+	}
+	for _, holidays := range other.days {
+		for _, h := range holidays {
+			c.Add(h)
+		}
+	}
+}
+
+// Lookup returns the holiday on the calendar date of t. Several holidays on
+// the same date are combined, with their distinct names and regions joined
+// by ", ".
+func (c *Calendar) Lookup(t time.Time) (Holiday, bool) {
+	if c == nil {
+		return Holiday{}, false
+	}
+	holidays := c.days[t.Format(dateFormat)]
+	if len(holidays) == 0 {
+		return Holiday{}, false
+	}
+	var names, regions []string
+	for _, h := range holidays {
+		names = appendDistinct(names, h.Name)
+		regions = appendDistinct(regions, h.Region)
+	}
+	return Holiday{
+		Date:   holidays[0].Date,
+		Name:   strings.Join(names, ", "),
+		Region: strings.Join(regions, ", "),
+	}, true
+}
+
+func appendDistinct(list []string, s string) []string {
+	if s == "" {
+		return list
+	}
+	for _, existing := range list {
+		if existing == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
+
+// Len returns the number of dates with at least one holiday.
+func (c *Calendar) Len() int {
+	if c == nil {
+		return 0
+	}
+	return len(c.days)
+}
+
+// LoadCalendar reads holidays from an iCalendar (.ics) file or a YAML date
+// list. region tags every holiday and overrides a region set in the file.
+//
+// YAML files list dates, optionally with a name and an inclusive end date:
+//
+//	region: CN
+//	holidays:
+//	  - 2024-01-01
+//	  - date: 2024-10-01
+//	    end: 2024-10-07
+//	    name: National Day
+func LoadCalendar(path, region string) (*Calendar, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read holiday calendar: %w", err)
+	}
+
+	var cal *Calendar
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".ics", ".ical":
+		cal, err = parseICS(data, region)
+	case ".yaml", ".yml":
+		cal, err = parseHolidayYAML(data, region)
+	default:
+		return nil, fmt.Errorf("unsupported holiday calendar %s, expected .ics or .yaml", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse holiday calendar %s: %w", path, err)
+	}
+	return cal, nil
+}
+
+// LoadCalendars loads and merges calendars given as "[REGION=]path". The
+// optional region tags every holiday in the file; relative paths are
+// resolved against dir.
+func LoadCalendars(specs []string, dir string) (*Calendar, error) {
+	cal := NewCalendar()
+	for _, spec := range specs {
+		region, path, ok := strings.Cut(spec, "=")
+		if !ok {
+			region, path = "", spec
+		}
+		if dir != "" && !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		loaded, err := LoadCalendar(path, region)
+		if err != nil {
+			return nil, err
+		}
+		cal.Merge(loaded)
+	}
+	return cal, nil
+}
+
+type holidayEntry struct {
+	Date string `yaml:"date"`
+	End  string `yaml:"end"`
+	Name string `yaml:"name"`
+}
+
+func (e *holidayEntry) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		e.Date = node.Value
+		return nil
+	}
+	type plain holidayEntry
+	return node.Decode((*plain)(e))
+}
+
+type holidayFile struct {
+	Region   string         `yaml:"region"`
+	Holidays []holidayEntry `yaml:"holidays"`
+}
+
+func parseHolidayYAML(data []byte, region string) (*Calendar, error) {
+	var file holidayFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+	if region == "" {
+		region = file.Region
+	}
+
+	cal := NewCalendar()
+	for _, entry := range file.Holidays {
+		if err := entry.addTo(cal, region); err != nil {
+			return nil, err
+		}
+	}
+	return cal, nil
+}
+
+func (e holidayEntry) addTo(cal *Calendar, region string) error {
+	start, err := time.Parse(dateFormat, e.Date)
+	if err != nil {
+		return fmt.Errorf("invalid holiday date %q", e.Date)
+	}
+	end := start
+	if e.End != "" {
+		if end, err = time.Parse(dateFormat, e.End); err != nil {
+			return fmt.Errorf("invalid holiday end date %q", e.End)
+		}
+	}
+	addRange(cal, start, end, e.Name, region)
+	return nil
+}
+
+// parseICS reads the all-day and timed VEVENTs of an iCalendar file.
+// Recurrence rules are not expanded.
+func parseICS(data []byte, region string) (*Calendar, error) {
+	// unfold continuation lines
+	data = bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+	data = bytes.ReplaceAll(data, []byte("\n "), nil)
+	data = bytes.ReplaceAll(data, []byte("\n\t"), nil)
+
+	cal := NewCalendar()
+	var inEvent bool
+	var start, end, summary string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		name, _, _ = strings.Cut(name, ";")
+		switch strings.ToUpper(name) {
+		case "BEGIN":
+			if strings.EqualFold(value, "VEVENT") {
+				inEvent, start, end, summary = true, "", "", ""
+			}
+		case "DTSTART":
+			start = value
+		case "DTEND":
+			end = value
+		case "SUMMARY":
+			summary = unescapeICS(value)
+		case "END":
+			if !inEvent || !strings.EqualFold(value, "VEVENT") {
+				continue
+			}
+			inEvent = false
+			if len(start) < 8 {
+				return nil, fmt.Errorf("event %q has no start date", summary)
+			}
+			first, err := time.Parse("20060102", start[:8])
+			if err != nil {
+				return nil, fmt.Errorf("invalid start date %q", start)
+			}
+			last := first
+			if len(end) >= 8 {
+				if last, err = time.Parse("20060102", end[:8]); err != nil {
+					return nil, fmt.Errorf("invalid end date %q", end)
+				}
+				// all-day events end on the following day
+				if len(end) == 8 && last.After(first) {
+					last = last.AddDate(0, 0, -1)
+				}
+			}
+			addRange(cal, first, last, summary, region)
+		}
+	}
+	return cal, scanner.Err()
+}
+
+func addRange(cal *Calendar, first, last time.Time, name, region string) {
+	if name == "" {
+		name = "Holiday"
+	}
+	for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+		cal.Add(Holiday{Date: day.Format(dateFormat), Name: name, Region: region})
+	}
+}
+
+func unescapeICS(s string) string {
+	r := strings.NewReplacer(`\,`, ",", `\;`, ";", `\n`, " ", `\N`, " ", `\\`, `\`)
+	return r.Replace(s)
+}
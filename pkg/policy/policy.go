@@ -0,0 +1,453 @@
+package policy
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Clock is a time of day in minutes after midnight.
+type Clock int
+
+func ParseClock(s string) (Clock, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q, expected HH:MM", s)
+	}
+	return Clock(t.Hour()*60 + t.Minute()), nil
+}
+
+func clockOf(t time.Time) Clock {
+	return Clock(t.Hour()*60 + t.Minute())
+}
+
+func (c Clock) String() string {
+	return fmt.Sprintf("%02d:%02d", int(c)/60, int(c)%60)
+}
+
+// Span is the half-open range [Start, End) of the day. A span whose end is
+// before its start wraps around midnight, and one whose end equals its
+// start, such as 00:00-00:00, is empty.
+type Span struct {
+	Start Clock
+	End   Clock
+}
+
+func ParseSpan(s string) (Span, error) {
+	parts := strings.Split(s, "-")
+	if len(parts) != 2 {
+		return Span{}, fmt.Errorf("invalid time range %q, expected HH:MM-HH:MM", s)
+	}
+	start, err := ParseClock(parts[0])
+	if err != nil {
+		return Span{}, err
+	}
+	end, err := ParseClock(parts[1])
+	if err != nil {
+		return Span{}, err
+	}
+	return Span{Start: start, End: end}, nil
+}
+
+func (s Span) Contains(c Clock) bool {
+	if s.Start <= s.End {
+		return c >= s.Start && c < s.End
+	}
+	return c >= s.Start || c < s.End
+}
+
+func (s Span) String() string {
+	return s.Start.String() + "-" + s.End.String()
+}
+
+// Policy describes when someone is expected to work.
+type Policy struct {
+	Name      string
+	WorkDays  map[time.Weekday]bool
+	Hours     Span
+	Breaks    []Span
+	LateNight Span
+	// Region selects the --holidays calendars that apply, "" for only those
+	// given without a region.
+	Region    string
+	Holidays  *Calendar
+	Vacations *Calendar
+}
+
+// Default is the policy used when none is configured: Monday to Friday,
+// 09:00-18:00, with late night from 23:00 to 06:00.
+func Default() *Policy {
+	return &Policy{
+		Name: "default",
+		WorkDays: map[time.Weekday]bool{
+			time.Monday: true, time.Tuesday: true, time.Wednesday: true,
+			time.Thursday: true, time.Friday: true,
+		},
+		Hours:     Span{Start: 9 * 60, End: 18 * 60},
+		LateNight: Span{Start: 23 * 60, End: 6 * 60},
+	}
+}
+
+// IsWeekend reports whether t falls on a day the policy does not work.
+func (p *Policy) IsWeekend(t time.Time) bool {
+	return !p.WorkDays[t.Weekday()]
+}
+
+func (p *Policy) IsLateNight(t time.Time) bool {
+	return p.LateNight.Contains(clockOf(t))
+}
+
+// Holiday returns the public holiday t falls on, if any.
+func (p *Policy) Holiday(t time.Time) (Holiday, bool) {
+	return p.Holidays.Lookup(t)
+}
+
+// TimeOff returns the vacation or public holiday t falls on, if any.
+func (p *Policy) TimeOff(t time.Time) (Holiday, bool) {
+	if h, ok := p.Vacations.Lookup(t); ok {
+		return h, true
+	}
+	return p.Holidays.Lookup(t)
+}
+
+// IsOutOfHours reports whether t is outside working time: on a day off, on
+// a holiday or vacation, outside working hours or during a break.
+func (p *Policy) IsOutOfHours(t time.Time) bool {
+	if p.IsWeekend(t) {
+		return true
+	}
+	if _, ok := p.TimeOff(t); ok {
+		return true
+	}
+	c := clockOf(t)
+	if !p.Hours.Contains(c) {
+		return true
+	}
+	for _, b := range p.Breaks {
+		if b.Contains(c) {
+			return true
+		}
+	}
+	return false
+}
+
+// String summarises the policy, e.g. "default (Mon-Fri 09:00-18:00)".
+func (p *Policy) String() string {
+	days := make([]string, 0, 7)
+	for d := time.Monday; d <= time.Saturday; d++ {
+		if p.WorkDays[d] {
+			days = append(days, d.String()[:3])
+		}
+	}
+	if p.WorkDays[time.Sunday] {
+		days = append(days, "Sun")
+	}
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "%s (%s %s", p.Name, strings.Join(days, ","), p.Hours)
+	for _, br := range p.Breaks {
+		fmt.Fprintf(b, ", break %s", br)
+	}
+	if n := p.Holidays.Len(); n > 0 {
+		fmt.Fprintf(b, ", %d holidays", n)
+	}
+	if n := p.Vacations.Len(); n > 0 {
+		fmt.Fprintf(b, ", %d vacation days", n)
+	}
+	b.WriteString(")")
+	return b.String()
+}
+
+// Set holds the default policy plus overrides for teams and authors.
+// Authors are matched by name or email, case-insensitively.
+type Set struct {
+	Default *Policy
+	teams   map[string]*Policy
+	members map[string]string
+	authors map[string]*Policy
+}
+
+// For returns the policy that applies to an author. A nil set always
+// returns the default policy.
+func (s *Set) For(author, email string) *Policy {
+	if s == nil {
+		return defaultPolicy
+	}
+	for _, key := range []string{strings.ToLower(author), strings.ToLower(email)} {
+		if key == "" {
+			continue
+		}
+		if p, ok := s.authors[key]; ok {
+			return p
+		}
+		if team, ok := s.members[key]; ok {
+			return s.teams[team]
+		}
+	}
+	return s.Default
+}
+
+var defaultPolicy = Default()
+
+// NewSet returns a set that applies the default policy to everyone.
+func NewSet() *Set {
+	return &Set{
+		Default: Default(),
+		teams:   make(map[string]*Policy),
+		members: make(map[string]string),
+		authors: make(map[string]*Policy),
+	}
+}
+
+// AddHolidays adds cal to the holidays of the policies of region, or of
+// every policy when region is "". It returns the number of policies the
+// holidays were added to.
+func (s *Set) AddHolidays(region string, cal *Calendar) int {
+	added := 0
+	add := func(p *Policy) {
+		if region != "" && !strings.EqualFold(p.Region, region) {
+			return
+		}
+		merged := NewCalendar()
+		merged.Merge(p.Holidays)
+		merged.Merge(cal)
+		p.Holidays = merged
+		added++
+	}
+	add(s.Default)
+	for _, p := range s.teams {
+		add(p)
+	}
+	for _, p := range s.authors {
+		add(p)
+	}
+	return added
+}
+
+// LoadHolidays loads calendars given as "[REGION=]path" and adds each to the
+// policies of its region, or to every policy when it has none. A region no
+// policy belongs to is an error rather than silently ignored.
+func (s *Set) LoadHolidays(specs []string) error {
+	for _, spec := range specs {
+		region, path, ok := strings.Cut(spec, "=")
+		if !ok {
+			region, path = "", spec
+		}
+		cal, err := LoadCalendar(path, region)
+		if err != nil {
+			return err
+		}
+		if s.AddHolidays(region, cal) == 0 {
+			return fmt.Errorf("holiday calendar %s is for region %s, but no policy has region: %s", path, region, region)
+		}
+	}
+	return nil
+}
+
+type policyConfig struct {
+	WorkDays  []string       `yaml:"work_days"`
+	Hours     string         `yaml:"hours"`
+	Breaks    []string       `yaml:"breaks"`
+	LateNight string         `yaml:"late_night"`
+	Region    string         `yaml:"region"`
+	Holidays  []string       `yaml:"holidays"`
+	Vacations []holidayEntry `yaml:"vacations"`
+	Members   []string       `yaml:"members"`
+}
+
+type setConfig struct {
+	Default policyConfig            `yaml:"default"`
+	Teams   map[string]policyConfig `yaml:"teams"`
+	Authors map[string]policyConfig `yaml:"authors"`
+}
+
+// Load reads a policy file:
+//
+//	default:
+//	  work_days: [mon, tue, wed, thu, fri]
+//	  hours: "09:00-18:00"
+//	  breaks: ["12:00-13:00"]
+//	  late_night: "23:00-06:00"
+//	  region: CN
+//	  holidays: [CN=holidays/cn.ics]
+//	teams:
+//	  platform:
+//	    members: [Alice, bob@example.com]
+//	    hours: "10:00-19:00"
+//	    region: US
+//	authors:
+//	  Carol:
+//	    work_days: [sun, mon, tue, wed, thu]
+//	    vacations:
+//	      - date: 2024-07-01
+//	        end: 2024-07-14
+//
+// Teams and authors inherit anything they leave out from the default.
+// Holidays are "[REGION=]path" and relative to the policy file; region picks
+// the calendars given with --holidays REGION=path. A late_night of
+// "00:00-00:00" turns late-night counting off.
+func Load(path string) (*Set, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy: %w", err)
+	}
+	var cfg setConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse policy %s: %w", path, err)
+	}
+
+	dir := filepath.Dir(path)
+	def, err := cfg.Default.apply(Default(), "default", dir)
+	if err != nil {
+		return nil, fmt.Errorf("%s: default: %w", path, err)
+	}
+	set := NewSet()
+	set.Default = def
+
+	teams := make([]string, 0, len(cfg.Teams))
+	for name := range cfg.Teams {
+		teams = append(teams, name)
+	}
+	sort.Strings(teams)
+	for _, name := range teams {
+		tc := cfg.Teams[name]
+		p, err := tc.apply(def, "team:"+name, dir)
+		if err != nil {
+			return nil, fmt.Errorf("%s: team %s: %w", path, name, err)
+		}
+		set.teams[name] = p
+		for _, member := range tc.Members {
+			set.members[strings.ToLower(member)] = name
+		}
+	}
+	for name, ac := range cfg.Authors {
+		p, err := ac.apply(def, "author:"+name, dir)
+		if err != nil {
+			return nil, fmt.Errorf("%s: author %s: %w", path, name, err)
+		}
+		set.authors[strings.ToLower(name)] = p
+	}
+	return set, nil
+}
+
+// TeamOf returns the team an author belongs to, or "" when they are in none.
+func (s *Set) TeamOf(author, email string) string {
+	if s == nil {
+		return ""
+	}
+	for _, key := range []string{strings.ToLower(author), strings.ToLower(email)} {
+		if team, ok := s.members[key]; ok && key != "" {
+			return team
+		}
+	}
+	return ""
+}
+
+// Teams returns the configured team names and their members.
+func (s *Set) Teams() map[string][]string {
+	teams := make(map[string][]string)
+	if s == nil {
+		return teams
+	}
+	for member, team := range s.members {
+		teams[team] = append(teams[team], member)
+	}
+	for _, members := range teams {
+		sort.Strings(members)
+	}
+	return teams
+}
+
+// LateNight describes the late-night window for reports: the default
+// policy's span, or "per policy" when a team or author has another one.
+func (s *Set) LateNight() string {
+	if s == nil {
+		return defaultPolicy.LateNight.String()
+	}
+	for _, p := range s.teams {
+		if p.LateNight != s.Default.LateNight {
+			return "per policy"
+		}
+	}
+	for _, p := range s.authors {
+		if p.LateNight != s.Default.LateNight {
+			return "per policy"
+		}
+	}
+	return s.Default.LateNight.String()
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+func (c policyConfig) apply(base *Policy, name, dir string) (*Policy, error) {
+	p := *base
+	p.Name = name
+	if len(c.WorkDays) > 0 {
+		p.WorkDays = make(map[time.Weekday]bool)
+		for _, d := range c.WorkDays {
+			key := strings.ToLower(strings.TrimSpace(d))
+			if len(key) > 3 {
+				key = key[:3]
+			}
+			day, ok := weekdays[key]
+			if !ok {
+				return nil, fmt.Errorf("invalid work day %q", d)
+			}
+			p.WorkDays[day] = true
+		}
+	}
+	if c.Hours != "" {
+		span, err := ParseSpan(c.Hours)
+		if err != nil {
+			return nil, err
+		}
+		p.Hours = span
+	}
+	if len(c.Breaks) > 0 {
+		p.Breaks = nil
+		for _, b := range c.Breaks {
+			span, err := ParseSpan(b)
+			if err != nil {
+				return nil, err
+			}
+			p.Breaks = append(p.Breaks, span)
+		}
+	}
+	if c.LateNight != "" {
+		span, err := ParseSpan(c.LateNight)
+		if err != nil {
+			return nil, err
+		}
+		p.LateNight = span
+	}
+	if c.Region != "" {
+		p.Region = c.Region
+	}
+	if len(c.Holidays) > 0 {
+		cal, err := LoadCalendars(c.Holidays, dir)
+		if err != nil {
+			return nil, err
+		}
+		p.Holidays = cal
+	}
+	if len(c.Vacations) > 0 {
+		cal := NewCalendar()
+		for _, v := range c.Vacations {
+			if v.Name == "" {
+				v.Name = "Vacation"
+			}
+			if err := v.addTo(cal, ""); err != nil {
+				return nil, err
+			}
+		}
+		p.Vacations = cal
+	}
+	return &p, nil
+}
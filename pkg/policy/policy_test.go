@@ -0,0 +1,185 @@
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSpanContains(t *testing.T) {
+	night, err := ParseSpan("23:00-06:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		clock string
+		want  bool
+	}{
+		{"23:00", true}, {"02:30", true}, {"05:59", true}, {"06:00", false}, {"12:00", false}, {"22:59", false},
+	} {
+		c, _ := ParseClock(tc.clock)
+		if got := night.Contains(c); got != tc.want {
+			t.Errorf("Contains(%s) = %v, want %v", tc.clock, got, tc.want)
+		}
+	}
+
+	off, err := ParseSpan("00:00-00:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, clock := range []Clock{0, 12 * 60, 23*60 + 59} {
+		if off.Contains(clock) {
+			t.Errorf("Expected 00:00-00:00 not to contain %s", clock)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	write("holidays.yaml", "holidays:\n  - 2024-05-01\n  - date: 2024-10-01\n    end: 2024-10-03\n    name: National Day\n")
+	path := write("policy.yaml", `
+default:
+  hours: "09:30-18:30"
+  breaks: ["12:00-13:00"]
+  holidays: [holidays.yaml]
+teams:
+  night-shift:
+    members: [Bob, carol@example.com]
+    work_days: [sat, sun]
+    hours: "20:00-04:00"
+authors:
+  Dave:
+    late_night: "01:00-05:00"
+  Erin:
+    late_night: "00:00-00:00"
+`)
+
+	set, err := Load(path)
+	if err != nil {
+		t.Fatalf("Failed to load policy: %v", err)
+	}
+
+	alice := set.For("Alice", "alice@example.com")
+	if alice.Name != "default" {
+		t.Errorf("Expected Alice to use the default policy, got %s", alice.Name)
+	}
+	tuesday := func(hour, min int) time.Time { return time.Date(2024, 1, 2, hour, min, 0, 0, time.UTC) }
+	if !alice.IsOutOfHours(tuesday(9, 0)) || alice.IsOutOfHours(tuesday(10, 0)) || !alice.IsOutOfHours(tuesday(12, 30)) {
+		t.Errorf("Unexpected working hours for the default policy")
+	}
+	if h, ok := alice.Holiday(time.Date(2024, 10, 2, 10, 0, 0, 0, time.UTC)); !ok || h.Name != "National Day" {
+		t.Errorf("Expected 2024-10-02 to be National Day, got %+v", h)
+	}
+	if !alice.IsOutOfHours(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected work on a holiday to be out of hours")
+	}
+
+	carol := set.For("Carol", "Carol@Example.com")
+	if carol.Name != "team:night-shift" {
+		t.Errorf("Expected Carol to use the night-shift policy, got %s", carol.Name)
+	}
+	saturdayNight := time.Date(2024, 1, 6, 22, 0, 0, 0, time.UTC)
+	if carol.IsWeekend(saturdayNight) || carol.IsOutOfHours(saturdayNight) {
+		t.Errorf("Expected Saturday 22:00 to be working time for the night shift")
+	}
+
+	dave := set.For("dave", "")
+	if dave.IsLateNight(tuesday(23, 30)) || !dave.IsLateNight(tuesday(2, 0)) {
+		t.Errorf("Expected Dave's late night to be 01:00-05:00")
+	}
+	if dave.Hours != alice.Hours {
+		t.Errorf("Expected Dave to inherit the default hours")
+	}
+
+	erin := set.For("Erin", "")
+	if erin.IsLateNight(tuesday(0, 0)) || erin.IsLateNight(tuesday(2, 0)) || erin.IsLateNight(tuesday(23, 30)) {
+		t.Errorf("Expected 00:00-00:00 to turn off Erin's late night")
+	}
+	if got := set.LateNight(); got != "per policy" {
+		t.Errorf("LateNight() = %q, want per policy", got)
+	}
+	if got := (*Set)(nil).LateNight(); got != "23:00-06:00" {
+		t.Errorf("nil LateNight() = %q", got)
+	}
+}
+
+func TestLoadCalendarICS(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "holidays.ics")
+	ics := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n" +
+		"BEGIN:VEVENT\r\nDTSTART;VALUE=DATE:20241225\r\nDTEND;VALUE=DATE:20241227\r\nSUMMARY:Christmas\\, Boxing\r\n  Day\r\nEND:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\nDTSTART;VALUE=DATE:20240101\r\nSUMMARY:New Year\r\nEND:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+	if err := os.WriteFile(path, []byte(ics), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cal, err := LoadCalendar(path, "UK")
+	if err != nil {
+		t.Fatalf("Failed to load calendar: %v", err)
+	}
+	if cal.Len() != 3 {
+		t.Errorf("Expected 3 holiday dates, got %d", cal.Len())
+	}
+	h, ok := cal.Lookup(time.Date(2024, 12, 26, 9, 0, 0, 0, time.UTC))
+	if !ok || h.Name != "Christmas, Boxing Day" || h.Region != "UK" {
+		t.Errorf("Unexpected holiday on 2024-12-26: %+v", h)
+	}
+	if _, ok := cal.Lookup(time.Date(2024, 12, 27, 9, 0, 0, 0, time.UTC)); ok {
+		t.Errorf("Expected the exclusive DTEND not to be a holiday")
+	}
+}
+
+func TestCalendarLookupCombinesHolidays(t *testing.T) {
+	cal := NewCalendar()
+	cal.Add(Holiday{Date: "2024-10-01", Name: "National Day", Region: "CN"})
+	cal.Add(Holiday{Date: "2024-10-01", Name: "Independence Day", Region: "NG"})
+	cal.Add(Holiday{Date: "2024-10-01", Name: "National Day", Region: "CN"})
+	h, ok := cal.Lookup(time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC))
+	if !ok || h.Name != "National Day, Independence Day" || h.Region != "CN, NG" {
+		t.Errorf("Lookup() = %+v, %v", h, ok)
+	}
+}
+
+func TestAddHolidaysByRegion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	if err := os.WriteFile(path, []byte("default:\n  region: CN\nauthors:\n  Bob:\n    region: US\n  Carol:\n    hours: \"10:00-19:00\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	set, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if set.For("Carol", "").Region != "CN" {
+		t.Errorf("Expected Carol to inherit the default region")
+	}
+
+	day := func(date string) *Calendar {
+		cal := NewCalendar()
+		cal.Add(Holiday{Date: date, Name: date})
+		return cal
+	}
+	if n := set.AddHolidays("us", day("2024-07-04")); n != 1 {
+		t.Errorf("Expected the US calendar to be added to one policy, got %d", n)
+	}
+	if n := set.AddHolidays("", day("2024-01-01")); n != 3 {
+		t.Errorf("Expected a calendar without region to be added to every policy, got %d", n)
+	}
+	july4 := time.Date(2024, 7, 4, 12, 0, 0, 0, time.UTC)
+	if _, ok := set.For("Bob", "").Holiday(july4); !ok {
+		t.Errorf("Expected 2024-07-04 to be a holiday for Bob")
+	}
+	if _, ok := set.For("Alice", "").Holiday(july4); ok {
+		t.Errorf("Expected 2024-07-04 not to be a holiday for Alice")
+	}
+	if _, ok := set.For("Alice", "").Holiday(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)); !ok {
+		t.Errorf("Expected 2024-01-01 to be a holiday for everyone")
+	}
+}
@@ -0,0 +1,174 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// repoResult is the part of a repository's stored results that Diff reads.
+type repoResult struct {
+	TotalCommits int `json:"total_commits"`
+	Statistics   struct {
+		CommitCountByAuthor map[string]int `json:"commit_count_by_author"`
+		LateNightCommits    struct {
+			Total int `json:"total"`
+		} `json:"late_night_commits"`
+	} `json:"statistics"`
+}
+
+func (r repoResult) lateNightShare() float64 {
+	if r.TotalCommits == 0 {
+		return 0
+	}
+	return float64(r.Statistics.LateNightCommits.Total) / float64(r.TotalCommits)
+}
+
+// AuthorChange is the change of an author's commit count in a repository.
+type AuthorChange struct {
+	Author string `json:"author"`
+	Before int    `json:"before"`
+	After  int    `json:"after"`
+}
+
+// RepoDiff is the change of a repository present in both snapshots.
+type RepoDiff struct {
+	Repository    string `json:"repository"`
+	CommitsBefore int    `json:"commits_before"`
+	CommitsAfter  int    `json:"commits_after"`
+	// NewAuthors committed for the first time since the older snapshot.
+	NewAuthors []string `json:"new_authors"`
+	// Authors whose commit count changed, largest increase first.
+	Authors              []AuthorChange `json:"authors"`
+	LateNightShareBefore float64        `json:"late_night_share_before"`
+	LateNightShareAfter  float64        `json:"late_night_share_after"`
+}
+
+// Changed reports whether anything differs between the snapshots.
+func (r RepoDiff) Changed() bool {
+	return r.CommitsBefore != r.CommitsAfter || len(r.Authors) > 0 || r.LateNightShareBefore != r.LateNightShareAfter
+}
+
+// Diff is the change between an older and a newer snapshot.
+type Diff struct {
+	From                string     `json:"from"`
+	FromTime            time.Time  `json:"from_time"`
+	To                  string     `json:"to"`
+	ToTime              time.Time  `json:"to_time"`
+	NewRepositories     []string   `json:"new_repositories"`
+	RemovedRepositories []string   `json:"removed_repositories"`
+	Repositories        []RepoDiff `json:"repositories"`
+	// Aggregate is the change over all repositories together, when both
+	// snapshots were taken with --aggregate.
+	Aggregate *RepoDiff `json:"aggregate,omitempty"`
+}
+
+// Compare computes what changed from the snapshot from to the snapshot to.
+// Snapshots of ticket listings have no statistics and cannot be compared.
+func Compare(from, to *Snapshot) (Diff, error) {
+	before, err := decodeResults(from)
+	if err != nil {
+		return Diff{}, err
+	}
+	after, err := decodeResults(to)
+	if err != nil {
+		return Diff{}, err
+	}
+
+	diff := Diff{
+		From:                from.ID,
+		FromTime:            from.Time,
+		To:                  to.ID,
+		ToTime:              to.Time,
+		NewRepositories:     []string{},
+		RemovedRepositories: []string{},
+		Repositories:        []RepoDiff{},
+	}
+	if from.Aggregate != nil && to.Aggregate != nil {
+		var old, cur repoResult
+		if err := json.Unmarshal(from.Aggregate, &old); err != nil {
+			return Diff{}, fmt.Errorf("snapshot %s has no statistics for the aggregate: %w", from.ID, err)
+		}
+		if err := json.Unmarshal(to.Aggregate, &cur); err != nil {
+			return Diff{}, fmt.Errorf("snapshot %s has no statistics for the aggregate: %w", to.ID, err)
+		}
+		rd := compareResults("aggregate", old, cur)
+		diff.Aggregate = &rd
+	}
+	for repo := range before {
+		if _, ok := after[repo]; !ok {
+			diff.RemovedRepositories = append(diff.RemovedRepositories, repo)
+		}
+	}
+	repos := make([]string, 0, len(after))
+	for repo := range after {
+		if _, ok := before[repo]; ok {
+			repos = append(repos, repo)
+		} else {
+			diff.NewRepositories = append(diff.NewRepositories, repo)
+		}
+	}
+	sort.Strings(diff.NewRepositories)
+	sort.Strings(diff.RemovedRepositories)
+	sort.Strings(repos)
+
+	for _, repo := range repos {
+		diff.Repositories = append(diff.Repositories, compareResults(repo, before[repo], after[repo]))
+	}
+	return diff, nil
+}
+
+func compareResults(repo string, old, cur repoResult) RepoDiff {
+	rd := RepoDiff{
+		Repository:           repo,
+		CommitsBefore:        old.TotalCommits,
+		CommitsAfter:         cur.TotalCommits,
+		NewAuthors:           []string{},
+		Authors:              []AuthorChange{},
+		LateNightShareBefore: old.lateNightShare(),
+		LateNightShareAfter:  cur.lateNightShare(),
+	}
+	authors := make(map[string]bool)
+	for author := range old.Statistics.CommitCountByAuthor {
+		authors[author] = true
+	}
+	for author := range cur.Statistics.CommitCountByAuthor {
+		authors[author] = true
+		if _, ok := old.Statistics.CommitCountByAuthor[author]; !ok {
+			rd.NewAuthors = append(rd.NewAuthors, author)
+		}
+	}
+	for author := range authors {
+		change := AuthorChange{
+			Author: author,
+			Before: old.Statistics.CommitCountByAuthor[author],
+			After:  cur.Statistics.CommitCountByAuthor[author],
+		}
+		if change.Before != change.After {
+			rd.Authors = append(rd.Authors, change)
+		}
+	}
+	sort.Strings(rd.NewAuthors)
+	sort.Slice(rd.Authors, func(i, j int) bool {
+		di := rd.Authors[i].After - rd.Authors[i].Before
+		dj := rd.Authors[j].After - rd.Authors[j].Before
+		if di != dj {
+			return di > dj
+		}
+		return rd.Authors[i].Author < rd.Authors[j].Author
+	})
+	return rd
+}
+
+func decodeResults(s *Snapshot) (map[string]repoResult, error) {
+	results := make(map[string]repoResult, len(s.Results))
+	for repo, raw := range s.Results {
+		var r repoResult
+		if err := json.Unmarshal(raw, &r); err != nil {
+			return nil, fmt.Errorf("snapshot %s has no statistics for %s: %w", s.ID, repo, err)
+		}
+		results[repo] = r
+	}
+	return results, nil
+}
@@ -0,0 +1,93 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	repo := func(total, lateNight int, authors map[string]int) json.RawMessage {
+		data, _ := json.Marshal(map[string]interface{}{
+			"total_commits": total,
+			"statistics": map[string]interface{}{
+				"commit_count_by_author": authors,
+				"late_night_commits":     map[string]interface{}{"total": lateNight},
+			},
+		})
+		return data
+	}
+	from := &Snapshot{ID: "old", Results: map[string]json.RawMessage{
+		"/src/app":  repo(4, 1, map[string]int{"Alice": 3, "Bob": 1}),
+		"/src/lib":  repo(2, 0, map[string]int{"Alice": 2}),
+		"/src/gone": repo(1, 0, map[string]int{"Bob": 1}),
+	}}
+	to := &Snapshot{ID: "new", Results: map[string]json.RawMessage{
+		"/src/app": repo(8, 4, map[string]int{"Alice": 4, "Bob": 1, "Carol": 3}),
+		"/src/lib": repo(2, 0, map[string]int{"Alice": 2}),
+		"/src/new": repo(5, 0, map[string]int{"Dave": 5}),
+	}}
+
+	diff, err := Compare(from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diff.NewRepositories) != 1 || diff.NewRepositories[0] != "/src/new" ||
+		len(diff.RemovedRepositories) != 1 || diff.RemovedRepositories[0] != "/src/gone" {
+		t.Errorf("repositories new %v, removed %v", diff.NewRepositories, diff.RemovedRepositories)
+	}
+	if len(diff.Repositories) != 2 {
+		t.Fatalf("repositories = %+v", diff.Repositories)
+	}
+	app, lib := diff.Repositories[0], diff.Repositories[1]
+	if app.CommitsBefore != 4 || app.CommitsAfter != 8 || app.LateNightShareBefore != 0.25 || app.LateNightShareAfter != 0.5 {
+		t.Errorf("app = %+v", app)
+	}
+	if len(app.NewAuthors) != 1 || app.NewAuthors[0] != "Carol" {
+		t.Errorf("new authors = %v", app.NewAuthors)
+	}
+	if len(app.Authors) != 2 || app.Authors[0] != (AuthorChange{"Carol", 0, 3}) || app.Authors[1] != (AuthorChange{"Alice", 3, 4}) {
+		t.Errorf("author changes = %+v", app.Authors)
+	}
+	if !app.Changed() || lib.Changed() {
+		t.Errorf("changed: app %v, lib %v", app.Changed(), lib.Changed())
+	}
+
+	if diff.Aggregate != nil {
+		t.Errorf("aggregate without --aggregate = %+v", diff.Aggregate)
+	}
+
+	// the aggregate is kept apart from a repository at the relative path
+	// "aggregate", and only compared when both snapshots have one
+	aggregate := func(total int, authors map[string]int) json.RawMessage {
+		data, _ := json.Marshal(map[string]interface{}{
+			"repositories":  2,
+			"total_commits": total,
+			"statistics":    map[string]interface{}{"commit_count_by_author": authors},
+		})
+		return data
+	}
+	from.Results["aggregate"] = repo(1, 0, map[string]int{"Erin": 1})
+	to.Results["aggregate"] = repo(2, 0, map[string]int{"Erin": 2})
+	to.Aggregate = aggregate(10, map[string]int{"Alice": 6, "Bob": 1, "Carol": 3})
+	if diff, err = Compare(from, to); err != nil {
+		t.Fatal(err)
+	}
+	if len(diff.NewRepositories) != 1 || len(diff.Repositories) != 3 || diff.Aggregate != nil {
+		t.Errorf("aggregate in newer snapshot only: new %v, aggregate %+v", diff.NewRepositories, diff.Aggregate)
+	}
+	from.Aggregate = aggregate(6, map[string]int{"Alice": 5, "Bob": 1})
+	if diff, err = Compare(from, to); err != nil {
+		t.Fatal(err)
+	}
+	if named := diff.Repositories[2]; named.Repository != "aggregate" || named.CommitsAfter != 2 {
+		t.Errorf("repository named aggregate = %+v", named)
+	}
+	if diff.Aggregate == nil || diff.Aggregate.CommitsBefore != 6 || diff.Aggregate.CommitsAfter != 10 || len(diff.Aggregate.NewAuthors) != 1 {
+		t.Errorf("aggregate in both snapshots = %+v", diff.Aggregate)
+	}
+
+	tickets := &Snapshot{ID: "tickets", Results: map[string]json.RawMessage{"/src/app": json.RawMessage(`[]`)}}
+	if _, err := Compare(from, tickets); err == nil {
+		t.Error("Compare(ticket listing) succeeded")
+	}
+}
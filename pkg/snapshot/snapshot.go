@@ -0,0 +1,187 @@
+// Package snapshot stores the results of git-watcher runs so that later runs
+// can be compared with earlier ones.
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// idFormat names snapshots after the UTC time of the run. Runs within the
+// same second get a suffix, as in 20240601T020000Z-2.
+const idFormat = "20060102T150405Z"
+
+// Snapshot is the stored result of one run.
+type Snapshot struct {
+	ID   string    `json:"id"`
+	Time time.Time `json:"time"`
+	// Root is the scanned directory.
+	Root string `json:"root"`
+	// Options are the command line flags the run was started with.
+	Options map[string]string `json:"options"`
+	// Results are the per-repository results as printed in JSON output.
+	Results map[string]json.RawMessage `json:"results"`
+	// Aggregate is the result over all repositories together of a run with
+	// --aggregate, kept out of Results whose keys are repository paths.
+	Aggregate json.RawMessage `json:"aggregate,omitempty"`
+}
+
+// Store is a directory of snapshots, one JSON file per run.
+type Store struct {
+	Dir string
+}
+
+// DefaultDir returns $XDG_DATA_HOME/git-watcher/snapshots, falling back to
+// ~/.local/share, or "" when no home directory is available.
+func DefaultDir() string {
+	data := os.Getenv("XDG_DATA_HOME")
+	if data == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		data = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(data, "git-watcher", "snapshots")
+}
+
+// Save stores results, which must marshal to a JSON object keyed by
+// repository, and aggregate, nil without --aggregate, as the snapshot taken
+// at now.
+func (s *Store) Save(now time.Time, root string, options map[string]string, results, aggregate interface{}) (*Snapshot, error) {
+	data, err := json.Marshal(results)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode results: %w", err)
+	}
+	snap := &Snapshot{
+		Time:    now,
+		Root:    root,
+		Options: options,
+	}
+	if err := json.Unmarshal(data, &snap.Results); err != nil {
+		return nil, fmt.Errorf("results are not keyed by repository: %w", err)
+	}
+	if data, err = json.Marshal(aggregate); err != nil {
+		return nil, fmt.Errorf("failed to encode aggregate: %w", err)
+	}
+	if string(data) != "null" {
+		snap.Aggregate = data
+	}
+
+	if err := os.MkdirAll(s.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+	base := now.UTC().Format(idFormat)
+	for n := 1; ; n++ {
+		snap.ID = base
+		if n > 1 {
+			snap.ID = fmt.Sprintf("%s-%d", base, n)
+		}
+		out, err := json.MarshalIndent(snap, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode snapshot: %w", err)
+		}
+		// never overwrite an earlier snapshot
+		f, err := os.OpenFile(filepath.Join(s.Dir, snap.ID+".json"), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if os.IsExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to write snapshot: %w", err)
+		}
+		_, werr := f.Write(out)
+		if cerr := f.Close(); werr == nil {
+			werr = cerr
+		}
+		if werr != nil {
+			os.Remove(f.Name())
+			return nil, fmt.Errorf("failed to write snapshot: %w", werr)
+		}
+		return snap, nil
+	}
+}
+
+// List returns the IDs of the stored snapshots, oldest first.
+func (s *Store) List() ([]string, error) {
+	entries, err := os.ReadDir(s.Dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshots: %w", err)
+	}
+	var ids []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".json") {
+			ids = append(ids, strings.TrimSuffix(e.Name(), ".json"))
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		ti, ni := splitID(ids[i])
+		tj, nj := splitID(ids[j])
+		if ti != tj {
+			return ti < tj
+		}
+		return ni < nj
+	})
+	return ids, nil
+}
+
+// splitID splits an ID into its time and its same-second sequence number.
+func splitID(id string) (string, int) {
+	base, suffix, ok := strings.Cut(id, "-")
+	if !ok {
+		return id, 1
+	}
+	n, err := strconv.Atoi(suffix)
+	if err != nil {
+		return id, 0
+	}
+	return base, n
+}
+
+// Load reads a snapshot by ID, by unique ID prefix such as a date
+// ("20240601"), or from a file path.
+func (s *Store) Load(ref string) (*Snapshot, error) {
+	path := ref
+	if _, err := os.Stat(ref); err != nil {
+		ids, err := s.List()
+		if err != nil {
+			return nil, err
+		}
+		var matches []string
+		for _, id := range ids {
+			if id == ref {
+				matches = []string{id}
+				break
+			}
+			if strings.HasPrefix(id, ref) {
+				matches = append(matches, id)
+			}
+		}
+		switch len(matches) {
+		case 0:
+			return nil, fmt.Errorf("snapshot %q not found in %s", ref, s.Dir)
+		case 1:
+			path = filepath.Join(s.Dir, matches[0]+".json")
+		default:
+			return nil, fmt.Errorf("snapshot %q is ambiguous: %s", ref, strings.Join(matches, ", "))
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+	var snap Snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot %s: %w", path, err)
+	}
+	return &snap, nil
+}
@@ -0,0 +1,64 @@
+package snapshot
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStore(t *testing.T) {
+	store := &Store{Dir: t.TempDir()}
+	if ids, err := store.List(); err != nil || len(ids) != 0 {
+		t.Fatalf("List() on empty store = %v, %v", ids, err)
+	}
+
+	results := map[string]interface{}{"/src/app": map[string]interface{}{"total_commits": 3}}
+	first, err := store.Save(time.Date(2024, 6, 1, 2, 0, 0, 0, time.UTC), "/src", map[string]string{"timezone": "UTC"}, results, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.ID != "20240601T020000Z" {
+		t.Errorf("ID = %s", first.ID)
+	}
+	if _, err := store.Save(time.Date(2024, 6, 2, 2, 0, 0, 0, time.UTC), "/src", nil, results, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	ids, err := store.List()
+	if err != nil || len(ids) != 2 || ids[0] != first.ID {
+		t.Fatalf("List() = %v, %v", ids, err)
+	}
+	loaded, err := store.Load("20240601")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Root != "/src" || loaded.Options["timezone"] != "UTC" || loaded.Aggregate != nil {
+		t.Errorf("loaded = %+v", loaded)
+	}
+	if decoded, err := decodeResults(loaded); err != nil || decoded["/src/app"].TotalCommits != 3 {
+		t.Errorf("results = %+v, %v", decoded, err)
+	}
+	if _, err := store.Load("202406"); err == nil || !strings.Contains(err.Error(), "ambiguous") {
+		t.Errorf("Load(ambiguous) error = %v", err)
+	}
+	if _, err := store.Load("2023"); err == nil {
+		t.Error("Load(missing) succeeded")
+	}
+	// runs within the same second do not overwrite each other
+	for i := 0; i < 10; i++ {
+		if _, err := store.Save(first.Time, "/src", nil, results, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ids, _ = store.List()
+	if len(ids) != 12 || ids[0] != first.ID || ids[1] != first.ID+"-2" || ids[10] != first.ID+"-11" {
+		t.Errorf("List() = %v", ids)
+	}
+	if loaded, err := store.Load(first.ID); err != nil || loaded.ID != first.ID {
+		t.Errorf("Load(exact ID) = %v, %v", loaded, err)
+	}
+
+	if _, err := store.Save(time.Now(), "/src", nil, []string{"not", "by", "repository"}, nil); err == nil {
+		t.Error("Save(list) succeeded")
+	}
+}
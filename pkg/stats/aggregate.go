@@ -0,0 +1,127 @@
+package stats
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"git-watcher/pkg/analyzer"
+)
+
+// AggregateCommits merges the commits of several repositories for
+// organisation-wide statistics. A commit found in more than one repository,
+// as in forks and clones, is kept once; file paths are prefixed with the
+// shortest trailing part of the repository path that is unique among the
+// repositories ("api", or "work/api" and "oss/api" when two are named api) so
+// that files of different repositories stay apart; and identities are
+// unified with UnifyIdentities.
+func AggregateCommits(commitsByRepo map[string][]analyzer.CommitInfo) []analyzer.CommitInfo {
+	repos := make([]string, 0, len(commitsByRepo))
+	for repo := range commitsByRepo {
+		repos = append(repos, repo)
+	}
+	sort.Strings(repos)
+
+	prefixes := repoPrefixes(repos)
+	seen := make(map[string]bool)
+	var merged []analyzer.CommitInfo
+	for _, repo := range repos {
+		name := prefixes[repo]
+		for _, commit := range commitsByRepo[repo] {
+			if seen[commit.Hash] {
+				continue
+			}
+			seen[commit.Hash] = true
+			files := make([]analyzer.FileChange, len(commit.Files))
+			for i, f := range commit.Files {
+				f.Path = name + "/" + f.Path
+				files[i] = f
+			}
+			commit.Files = files
+			merged = append(merged, commit)
+		}
+	}
+	return UnifyIdentities(merged)
+}
+
+// repoPrefixes names each repository by the fewest trailing path elements
+// that no other repository shares.
+func repoPrefixes(repos []string) map[string]string {
+	parts := make(map[string][]string, len(repos))
+	depth := make(map[string]int, len(repos))
+	for _, repo := range repos {
+		parts[repo] = strings.Split(strings.Trim(filepath.ToSlash(filepath.Clean(repo)), "/"), "/")
+		depth[repo] = 1
+	}
+	label := func(repo string) string {
+		p := parts[repo]
+		n := depth[repo]
+		if n > len(p) {
+			n = len(p)
+		}
+		return strings.Join(p[len(p)-n:], "/")
+	}
+	for {
+		byLabel := make(map[string][]string)
+		for _, repo := range repos {
+			byLabel[label(repo)] = append(byLabel[label(repo)], repo)
+		}
+		grew := false
+		for _, same := range byLabel {
+			if len(same) < 2 {
+				continue
+			}
+			for _, repo := range same {
+				if depth[repo] < len(parts[repo]) {
+					depth[repo]++
+					grew = true
+				}
+			}
+		}
+		if !grew {
+			break
+		}
+	}
+	prefixes := make(map[string]string, len(repos))
+	for _, repo := range repos {
+		prefixes[repo] = label(repo)
+	}
+	return prefixes
+}
+
+// UnifyIdentities gives every commit by the same email, ignoring case, the
+// author name used most often with that email, so that one person committing
+// as "alice" in one repository and "Alice Smith" in another counts once.
+// Commits without an email keep their name.
+func UnifyIdentities(commits []analyzer.CommitInfo) []analyzer.CommitInfo {
+	names := make(map[string]map[string]int)
+	for _, commit := range commits {
+		email := strings.ToLower(commit.Email)
+		if email == "" {
+			continue
+		}
+		if names[email] == nil {
+			names[email] = make(map[string]int)
+		}
+		names[email][commit.Author]++
+	}
+	canonical := make(map[string]string, len(names))
+	for email, counts := range names {
+		best := ""
+		for name, n := range counts {
+			if best == "" || n > counts[best] || (n == counts[best] && name < best) {
+				best = name
+			}
+		}
+		canonical[email] = best
+	}
+
+	unified := make([]analyzer.CommitInfo, len(commits))
+	for i, commit := range commits {
+		if name, ok := canonical[strings.ToLower(commit.Email)]; ok {
+			commit.Author = name
+		}
+		unified[i] = commit
+	}
+	return unified
+}
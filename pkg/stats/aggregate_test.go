@@ -0,0 +1,49 @@
+package stats
+
+import (
+	"testing"
+
+	"git-watcher/pkg/analyzer"
+)
+
+func TestAggregateCommits(t *testing.T) {
+	commit := func(hash, author, email, file string) analyzer.CommitInfo {
+		return analyzer.CommitInfo{Hash: hash, Author: author, Email: email, Files: []analyzer.FileChange{{Path: file, Additions: 1}}}
+	}
+	shared := commit("c1", "alice", "Alice@example.com", "README.md")
+	merged := AggregateCommits(map[string][]analyzer.CommitInfo{
+		"/src/api": {shared, commit("a2", "Alice Smith", "alice@example.com", "main.go")},
+		"/src/web": {shared, commit("w2", "Alice Smith", "alice@example.com", "README.md"), commit("w3", "Bob", "", "index.html")},
+	})
+
+	if len(merged) != 4 {
+		t.Fatalf("merged %d commits, want 4 with the shared commit kept once", len(merged))
+	}
+	paths := map[string]string{}
+	for _, c := range merged {
+		paths[c.Hash] = c.Files[0].Path
+		if c.Hash != "w3" && c.Author != "Alice Smith" {
+			t.Errorf("%s author = %q, want the unified Alice Smith", c.Hash, c.Author)
+		}
+	}
+	if paths["c1"] != "api/README.md" || paths["w2"] != "web/README.md" || paths["w3"] != "web/index.html" {
+		t.Errorf("paths = %v", paths)
+	}
+	if shared.Files[0].Path != "README.md" || shared.Author != "alice" {
+		t.Errorf("input commit was modified: %+v", shared)
+	}
+
+	// repositories with the same name stay apart
+	merged = AggregateCommits(map[string][]analyzer.CommitInfo{
+		"/home/me/work/api": {commit("x1", "Bob", "", "main.go")},
+		"/home/me/oss/api":  {commit("y1", "Bob", "", "main.go")},
+		"/home/me/oss/web":  {commit("z1", "Bob", "", "main.go")},
+	})
+	paths = map[string]string{}
+	for _, c := range merged {
+		paths[c.Hash] = c.Files[0].Path
+	}
+	if paths["x1"] != "work/api/main.go" || paths["y1"] != "oss/api/main.go" || paths["z1"] != "web/main.go" {
+		t.Errorf("paths = %v", paths)
+	}
+}
@@ -0,0 +1,65 @@
+package stats
+
+import (
+	"time"
+
+	"git-watcher/pkg/analyzer"
+)
+
+const calendarDateFormat = "2006-01-02"
+
+// ContributionCalendar counts commits per day over the Days days ending at
+// End, like the contribution graph on a GitHub profile. A zero End means
+// today and a zero Days means one year.
+type ContributionCalendar struct {
+	Days int
+	End  time.Time
+}
+
+type Calendar struct {
+	Start   string                    `json:"start"`
+	End     string                    `json:"end"`
+	Max     int                       `json:"max"`
+	Days    map[string]int            `json:"days"`
+	Authors map[string]map[string]int `json:"authors"`
+}
+
+func (c *ContributionCalendar) Name() string {
+	return "contribution_calendar"
+}
+
+func (c *ContributionCalendar) Calculate(commits []analyzer.CommitInfo) interface{} {
+	end := c.End
+	if end.IsZero() {
+		end = time.Now()
+	}
+	days := c.Days
+	if days <= 0 {
+		days = 365
+	}
+	last := time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, time.UTC)
+	first := last.AddDate(0, 0, 1-days)
+
+	calendar := Calendar{
+		Start:   first.Format(calendarDateFormat),
+		End:     last.Format(calendarDateFormat),
+		Days:    make(map[string]int),
+		Authors: make(map[string]map[string]int),
+	}
+	for _, commit := range commits {
+		day := time.Date(commit.Date.Year(), commit.Date.Month(), commit.Date.Day(), 0, 0, 0, 0, time.UTC)
+		if day.Before(first) || day.After(last) {
+			continue
+		}
+		key := day.Format(calendarDateFormat)
+		calendar.Days[key]++
+		if calendar.Days[key] > calendar.Max {
+			calendar.Max = calendar.Days[key]
+		}
+		if calendar.Authors[commit.Author] == nil {
+			calendar.Authors[commit.Author] = make(map[string]int)
+		}
+		calendar.Authors[commit.Author][key]++
+	}
+	return calendar
+}
@@ -0,0 +1,37 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"git-watcher/pkg/analyzer"
+)
+
+func TestContributionCalendar(t *testing.T) {
+	end := time.Date(2024, 12, 31, 12, 0, 0, 0, time.UTC)
+	commits := []analyzer.CommitInfo{
+		{Author: "Alice", Date: time.Date(2024, 12, 30, 9, 0, 0, 0, time.UTC)},
+		{Author: "Bob", Date: time.Date(2024, 12, 30, 23, 0, 0, 0, time.FixedZone("UTC+8", 8*3600))},
+		{Author: "Alice", Date: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)},
+		{Author: "Alice", Date: time.Date(2023, 12, 31, 10, 0, 0, 0, time.UTC)}, // outside the year
+	}
+
+	stat := &ContributionCalendar{End: end}
+	result := stat.Calculate(commits).(Calendar)
+
+	if result.Start != "2024-01-02" || result.End != "2024-12-31" {
+		t.Errorf("Unexpected calendar range %s..%s", result.Start, result.End)
+	}
+	if result.Days["2024-12-30"] != 2 {
+		t.Errorf("Expected 2 commits on 2024-12-30, got %d", result.Days["2024-12-30"])
+	}
+	if _, ok := result.Days["2024-01-01"]; ok {
+		t.Errorf("Expected 2024-01-01 to fall outside the calendar")
+	}
+	if result.Max != 2 {
+		t.Errorf("Expected max of 2, got %d", result.Max)
+	}
+	if result.Authors["Bob"]["2024-12-30"] != 1 {
+		t.Errorf("Expected Bob to have 1 commit on 2024-12-30, got %d", result.Authors["Bob"]["2024-12-30"])
+	}
+}
@@ -0,0 +1,147 @@
+package stats
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"git-watcher/pkg/analyzer"
+)
+
+// Cohorts groups authors by the month of their first commit and follows how
+// many of each cohort are still committing Horizons months later. Authors
+// without a commit in the InactiveAfter before the repository's latest commit
+// are reported as inactive.
+type Cohorts struct {
+	Horizons      []int
+	InactiveAfter time.Duration
+}
+
+type Cohort struct {
+	Month     string   `json:"month"`
+	Newcomers []string `json:"newcomers"`
+	// Retained counts newcomers with a commit at least N months after
+	// their first month, keyed "Nm". Horizons the repository has not
+	// reached yet are left out.
+	Retained map[string]int `json:"retained"`
+	Inactive int            `json:"inactive"`
+}
+
+type InactiveAuthor struct {
+	Author      string `json:"author"`
+	FirstCommit string `json:"first_commit"`
+	LastCommit  string `json:"last_commit"`
+	Commits     int    `json:"commits"`
+}
+
+type CohortReport struct {
+	Horizons []int            `json:"horizons"`
+	Cohorts  []Cohort         `json:"cohorts"`
+	Inactive []InactiveAuthor `json:"inactive"`
+}
+
+// HorizonKey is the key of a horizon in Cohort.Retained.
+func HorizonKey(months int) string {
+	return fmt.Sprintf("%dm", months)
+}
+
+func (c *Cohorts) Name() string {
+	return "cohorts"
+}
+
+func (c *Cohorts) Calculate(commits []analyzer.CommitInfo) interface{} {
+	horizons := c.Horizons
+	if len(horizons) == 0 {
+		horizons = []int{3, 6, 12}
+	}
+	inactiveAfter := c.InactiveAfter
+	if inactiveAfter <= 0 {
+		inactiveAfter = 90 * 24 * time.Hour
+	}
+	report := CohortReport{Horizons: horizons, Cohorts: []Cohort{}, Inactive: []InactiveAuthor{}}
+	if len(commits) == 0 {
+		return report
+	}
+
+	type span struct {
+		first, last time.Time
+		commits     int
+	}
+	authors := make(map[string]*span)
+	latest := commits[0].Date
+	for _, commit := range commits {
+		if commit.Date.After(latest) {
+			latest = commit.Date
+		}
+		s := authors[commit.Author]
+		if s == nil {
+			authors[commit.Author] = &span{first: commit.Date, last: commit.Date, commits: 1}
+			continue
+		}
+		s.commits++
+		if commit.Date.Before(s.first) {
+			s.first = commit.Date
+		}
+		if commit.Date.After(s.last) {
+			s.last = commit.Date
+		}
+	}
+	inactiveSince := latest.Add(-inactiveAfter)
+
+	cohorts := make(map[int]*Cohort)
+	for author, s := range authors {
+		month := monthIndex(s.first)
+		cohort := cohorts[month]
+		if cohort == nil {
+			cohort = &Cohort{
+				Month:     s.first.Format("2006-01"),
+				Newcomers: []string{},
+				Retained:  make(map[string]int),
+			}
+			for _, h := range horizons {
+				if month+h <= monthIndex(latest) {
+					cohort.Retained[HorizonKey(h)] = 0
+				}
+			}
+			cohorts[month] = cohort
+		}
+		cohort.Newcomers = append(cohort.Newcomers, author)
+		for _, h := range horizons {
+			key := HorizonKey(h)
+			if _, reached := cohort.Retained[key]; reached && monthIndex(s.last) >= month+h {
+				cohort.Retained[key]++
+			}
+		}
+		if s.last.Before(inactiveSince) {
+			cohort.Inactive++
+			report.Inactive = append(report.Inactive, InactiveAuthor{
+				Author:      author,
+				FirstCommit: s.first.Format(calendarDateFormat),
+				LastCommit:  s.last.Format(calendarDateFormat),
+				Commits:     s.commits,
+			})
+		}
+	}
+
+	months := make([]int, 0, len(cohorts))
+	for month := range cohorts {
+		months = append(months, month)
+	}
+	sort.Ints(months)
+	for _, month := range months {
+		cohort := cohorts[month]
+		sort.Strings(cohort.Newcomers)
+		report.Cohorts = append(report.Cohorts, *cohort)
+	}
+	sort.Slice(report.Inactive, func(i, j int) bool {
+		if report.Inactive[i].LastCommit != report.Inactive[j].LastCommit {
+			return report.Inactive[i].LastCommit > report.Inactive[j].LastCommit
+		}
+		return report.Inactive[i].Author < report.Inactive[j].Author
+	})
+	return report
+}
+
+func monthIndex(t time.Time) int {
+	return t.Year()*12 + int(t.Month()) - 1
+}
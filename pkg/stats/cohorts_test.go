@@ -0,0 +1,46 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"git-watcher/pkg/analyzer"
+)
+
+func TestCohorts(t *testing.T) {
+	at := func(author string, year int, month time.Month, day int) analyzer.CommitInfo {
+		return analyzer.CommitInfo{Author: author, Date: time.Date(year, month, day, 12, 0, 0, 0, time.UTC)}
+	}
+	commits := []analyzer.CommitInfo{
+		at("Alice", 2023, 1, 10), at("Alice", 2023, 12, 1), at("Alice", 2024, 6, 1),
+		at("Bob", 2023, 1, 20), at("Bob", 2023, 3, 1),
+		at("Carol", 2023, 1, 5), at("Carol", 2023, 7, 15),
+		at("Dave", 2024, 4, 1), at("Dave", 2024, 6, 10),
+	}
+
+	stat := &Cohorts{}
+	report := stat.Calculate(commits).(CohortReport)
+
+	if len(report.Cohorts) != 2 {
+		t.Fatalf("Expected 2 cohorts, got %+v", report.Cohorts)
+	}
+	jan := report.Cohorts[0]
+	if jan.Month != "2023-01" || len(jan.Newcomers) != 3 || jan.Newcomers[0] != "Alice" {
+		t.Errorf("Unexpected January cohort: %+v", jan)
+	}
+	if jan.Retained["3m"] != 2 || jan.Retained["6m"] != 2 || jan.Retained["12m"] != 1 {
+		t.Errorf("Unexpected January retention: %v", jan.Retained)
+	}
+	if jan.Inactive != 2 {
+		t.Errorf("Expected Bob and Carol to be inactive, got %d", jan.Inactive)
+	}
+
+	apr := report.Cohorts[1]
+	if _, ok := apr.Retained["3m"]; ok {
+		t.Errorf("Expected the 3 month horizon not to be reached for April 2024, got %v", apr.Retained)
+	}
+
+	if len(report.Inactive) != 2 || report.Inactive[0].Author != "Carol" || report.Inactive[1].LastCommit != "2023-03-01" {
+		t.Errorf("Unexpected inactive authors: %+v", report.Inactive)
+	}
+}
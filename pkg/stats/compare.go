@@ -0,0 +1,133 @@
+package stats
+
+import (
+	"sort"
+	"time"
+
+	"git-watcher/pkg/analyzer"
+)
+
+// RepoComparison is one repository's row of a Comparison.
+type RepoComparison struct {
+	Repository string `json:"repository"`
+	Commits    int    `json:"commits"`
+	Authors    int    `json:"authors"`
+	// ActiveAuthors committed in the window before the latest commit of
+	// all compared repositories; RecentCommits are their commits in it.
+	ActiveAuthors          int     `json:"active_authors"`
+	RecentCommits          int     `json:"recent_commits"`
+	CommitsPerActiveAuthor float64 `json:"commits_per_active_author"`
+	LateNightRatio         float64 `json:"late_night_ratio"`
+	WeekendRatio           float64 `json:"weekend_ratio"`
+	// Hourly is the share of commits in each hour of the day.
+	Hourly []float64 `json:"hourly"`
+	// Trend is the commits per active author in each of the comparison's
+	// periods, counting the authors active in that period.
+	Trend []float64 `json:"trend"`
+}
+
+type Comparison struct {
+	Bucket       TrendBucket      `json:"bucket"`
+	Periods      []string         `json:"periods"`
+	WindowDays   int              `json:"window_days"`
+	Repositories []RepoComparison `json:"repositories"`
+}
+
+// Compare puts repositories side by side, normalising volumes per active
+// contributor so that large and small teams can be compared. The window is
+// opts.InactiveAfter, trend periods follow opts.TrendBucket and the last
+// periods of them are kept (all when periods <= 0). Dates are taken in
+// opts.TimeZone and late-night and weekend follow opts.Policies.
+func Compare(commitsByRepo map[string][]analyzer.CommitInfo, opts Options, periods int) Comparison {
+	repos := make([]string, 0, len(commitsByRepo))
+	var all []analyzer.CommitInfo
+	var latest time.Time
+	local := make(map[string][]analyzer.CommitInfo, len(commitsByRepo))
+	for repo, commits := range commitsByRepo {
+		repos = append(repos, repo)
+		local[repo] = opts.TimeZone.Localize(commits)
+		all = append(all, local[repo]...)
+		for _, commit := range commits {
+			if commit.Date.After(latest) {
+				latest = commit.Date
+			}
+		}
+	}
+	sort.Strings(repos)
+
+	bucket := opts.TrendBucket
+	if bucket == "" {
+		bucket = BucketMonth
+	}
+	comparison := Comparison{
+		Bucket:       bucket,
+		Periods:      []string{},
+		WindowDays:   int(opts.InactiveAfter / (24 * time.Hour)),
+		Repositories: []RepoComparison{},
+	}
+	for _, p := range (&Trend{Bucket: bucket}).Calculate(all).(TrendReport).Series {
+		comparison.Periods = append(comparison.Periods, p.Start)
+	}
+	if periods > 0 && len(comparison.Periods) > periods {
+		comparison.Periods = comparison.Periods[len(comparison.Periods)-periods:]
+	}
+
+	since := latest.Add(-opts.InactiveAfter)
+	for _, repo := range repos {
+		commits := local[repo]
+		row := RepoComparison{Repository: repo, Commits: len(commits), Hourly: make([]float64, 24)}
+
+		authors := make(map[string]bool)
+		active := make(map[string]bool)
+		lateNight, weekend := 0, 0
+		for _, commit := range commits {
+			authors[commit.Author] = true
+			if opts.InactiveAfter <= 0 || !commit.Date.Before(since) {
+				active[commit.Author] = true
+				row.RecentCommits++
+			}
+			p := opts.Policies.For(commit.Author, commit.Email)
+			if p.IsLateNight(commit.Date) {
+				lateNight++
+			}
+			if p.IsWeekend(commit.Date) {
+				weekend++
+			}
+			row.Hourly[commit.Date.Hour()]++
+		}
+		row.Authors = len(authors)
+		row.ActiveAuthors = len(active)
+		row.CommitsPerActiveAuthor = ratio(float64(row.RecentCommits), float64(row.ActiveAuthors))
+		row.LateNightRatio = ratio(float64(lateNight), float64(row.Commits))
+		row.WeekendRatio = ratio(float64(weekend), float64(row.Commits))
+		for h := range row.Hourly {
+			row.Hourly[h] = ratio(row.Hourly[h], float64(row.Commits))
+		}
+
+		series := make(map[string]TrendPoint)
+		for _, p := range (&Trend{Bucket: bucket}).Calculate(commits).(TrendReport).Series {
+			series[p.Start] = p
+		}
+		row.Trend = make([]float64, len(comparison.Periods))
+		for i, period := range comparison.Periods {
+			p := series[period]
+			row.Trend[i] = ratio(float64(p.Commits), float64(p.ActiveAuthors))
+		}
+		comparison.Repositories = append(comparison.Repositories, row)
+	}
+	return comparison
+}
+
+// PeakHours returns the n hours with the largest share of commits, busiest
+// first.
+func (r RepoComparison) PeakHours(n int) []int {
+	hours := make([]int, 24)
+	for h := range hours {
+		hours[h] = h
+	}
+	sort.SliceStable(hours, func(i, j int) bool { return r.Hourly[hours[i]] > r.Hourly[hours[j]] })
+	if n < len(hours) {
+		hours = hours[:n]
+	}
+	return hours
+}
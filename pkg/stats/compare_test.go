@@ -0,0 +1,50 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"git-watcher/pkg/analyzer"
+)
+
+func TestCompare(t *testing.T) {
+	at := func(month time.Month, day, hour int) time.Time { return time.Date(2024, month, day, hour, 0, 0, 0, time.UTC) }
+	commit := func(author string, date time.Time) analyzer.CommitInfo {
+		return analyzer.CommitInfo{Author: author, Date: date}
+	}
+	// 2024-06-01 and 2024-06-08 are Saturdays
+	small := []analyzer.CommitInfo{
+		commit("Alice", at(5, 6, 10)),
+		commit("Alice", at(6, 3, 10)),
+		commit("Alice", at(6, 8, 23)),
+	}
+	large := []analyzer.CommitInfo{
+		commit("Bob", at(1, 2, 10)),
+		commit("Carol", at(6, 3, 14)),
+		commit("Dave", at(6, 4, 14)),
+		commit("Carol", at(6, 5, 14)),
+	}
+	opts := DefaultOptions()
+	opts.InactiveAfter = 30 * 24 * time.Hour
+	c := Compare(map[string][]analyzer.CommitInfo{"/src/small": small, "/src/large": large}, opts, 2)
+
+	if len(c.Periods) != 2 || c.Periods[0] != "2024-05" || c.Periods[1] != "2024-06" || c.WindowDays != 30 {
+		t.Fatalf("comparison = %+v", c)
+	}
+	l, s := c.Repositories[0], c.Repositories[1]
+	if l.Repository != "/src/large" || s.Repository != "/src/small" {
+		t.Fatalf("repositories out of order: %s, %s", l.Repository, s.Repository)
+	}
+	if l.Authors != 3 || l.ActiveAuthors != 2 || l.RecentCommits != 3 || l.CommitsPerActiveAuthor != 1.5 {
+		t.Errorf("large = %+v", l)
+	}
+	if s.ActiveAuthors != 1 || s.CommitsPerActiveAuthor != 2 || s.LateNightRatio != 1.0/3 || s.WeekendRatio != 1.0/3 {
+		t.Errorf("small = %+v", s)
+	}
+	if l.Hourly[14] != 0.75 || l.PeakHours(1)[0] != 14 {
+		t.Errorf("large hourly = %v", l.Hourly)
+	}
+	if l.Trend[0] != 0 || l.Trend[1] != 1.5 || s.Trend[0] != 1 || s.Trend[1] != 2 {
+		t.Errorf("trends = %v, %v", l.Trend, s.Trend)
+	}
+}
@@ -0,0 +1,104 @@
+package stats
+
+import (
+	"strings"
+
+	"git-watcher/pkg/analyzer"
+)
+
+// ConventionalCommits breaks commits down by Conventional Commits type and
+// scope, per author and per trend bucket, and measures how many follow the
+// convention. When Types is set, other types do not count as compliant.
+// Merge commits generated by git are left out.
+type ConventionalCommits struct {
+	Types  []string
+	Bucket TrendBucket
+}
+
+type ConventionalBreakdown struct {
+	Commits    int            `json:"commits"`
+	Conforming int            `json:"conforming"`
+	Compliance float64        `json:"compliance"`
+	Breaking   int            `json:"breaking"`
+	Types      map[string]int `json:"types"`
+	Scopes     map[string]int `json:"scopes"`
+}
+
+type ConventionalReport struct {
+	ConventionalBreakdown
+	Authors map[string]*ConventionalBreakdown `json:"authors"`
+	Bucket  TrendBucket                       `json:"bucket"`
+	Periods map[string]*ConventionalBreakdown `json:"periods"`
+}
+
+func newConventionalBreakdown() *ConventionalBreakdown {
+	return &ConventionalBreakdown{Types: make(map[string]int), Scopes: make(map[string]int)}
+}
+
+func (b *ConventionalBreakdown) add(cc *analyzer.ConventionalCommit, conforming bool) {
+	b.Commits++
+	if cc == nil {
+		return
+	}
+	b.Types[cc.Type]++
+	if cc.Scope != "" {
+		b.Scopes[cc.Scope]++
+	}
+	if cc.Breaking {
+		b.Breaking++
+	}
+	if conforming {
+		b.Conforming++
+	}
+}
+
+func (c *ConventionalCommits) Name() string {
+	return "conventional_commits"
+}
+
+func (c *ConventionalCommits) Calculate(commits []analyzer.CommitInfo) interface{} {
+	bucket := c.Bucket
+	if bucket == "" {
+		bucket = BucketMonth
+	}
+	allowed := make(map[string]bool, len(c.Types))
+	for _, t := range c.Types {
+		allowed[strings.ToLower(t)] = true
+	}
+
+	report := ConventionalReport{
+		ConventionalBreakdown: *newConventionalBreakdown(),
+		Authors:               make(map[string]*ConventionalBreakdown),
+		Bucket:                bucket,
+		Periods:               make(map[string]*ConventionalBreakdown),
+	}
+	for _, commit := range commits {
+		if strings.HasPrefix(commit.Message, "Merge ") {
+			continue
+		}
+		cc := commit.Conventional
+		conforming := cc != nil && (len(allowed) == 0 || allowed[cc.Type])
+
+		author := report.Authors[commit.Author]
+		if author == nil {
+			author = newConventionalBreakdown()
+			report.Authors[commit.Author] = author
+		}
+		period := bucket.label(bucket.start(commit.Date))
+		if report.Periods[period] == nil {
+			report.Periods[period] = newConventionalBreakdown()
+		}
+		for _, b := range []*ConventionalBreakdown{&report.ConventionalBreakdown, author, report.Periods[period]} {
+			b.add(cc, conforming)
+		}
+	}
+
+	for _, b := range report.Authors {
+		b.Compliance = ratio(float64(b.Conforming), float64(b.Commits))
+	}
+	for _, b := range report.Periods {
+		b.Compliance = ratio(float64(b.Conforming), float64(b.Commits))
+	}
+	report.Compliance = ratio(float64(report.Conforming), float64(report.Commits))
+	return report
+}
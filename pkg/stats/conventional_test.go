@@ -0,0 +1,48 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"git-watcher/pkg/analyzer"
+)
+
+func TestConventionalCommits(t *testing.T) {
+	commit := func(author, message string, month time.Month) analyzer.CommitInfo {
+		return analyzer.CommitInfo{
+			Author:       author,
+			Message:      message,
+			Date:         time.Date(2024, month, 10, 12, 0, 0, 0, time.UTC),
+			Conventional: analyzer.ParseConventionalCommit(message),
+		}
+	}
+	commits := []analyzer.CommitInfo{
+		commit("Alice", "feat(api): add users endpoint", 1),
+		commit("Alice", "fix(api)!: reject empty names", 1),
+		commit("Alice", "wip: half done", 2),
+		commit("Bob", "Update stuff", 2),
+		commit("Bob", "docs: typo", 2),
+		commit("Bob", "Merge branch 'main' into dev", 2),
+	}
+
+	stat := &ConventionalCommits{Types: []string{"feat", "fix", "docs"}}
+	report := stat.Calculate(commits).(ConventionalReport)
+
+	if report.Commits != 5 || report.Conforming != 3 || report.Compliance != 0.6 || report.Breaking != 1 {
+		t.Errorf("Unexpected totals: %+v", report.ConventionalBreakdown)
+	}
+	if report.Types["feat"] != 1 || report.Types["wip"] != 1 || report.Scopes["api"] != 2 {
+		t.Errorf("Unexpected types or scopes: %v %v", report.Types, report.Scopes)
+	}
+	if bob := report.Authors["Bob"]; bob.Commits != 2 || bob.Compliance != 0.5 {
+		t.Errorf("Unexpected breakdown for Bob: %+v", bob)
+	}
+	if jan, feb := report.Periods["2024-01"], report.Periods["2024-02"]; jan.Compliance != 1 || feb.Conforming != 1 || feb.Commits != 3 {
+		t.Errorf("Unexpected periods: %+v %+v", jan, feb)
+	}
+
+	unrestricted := (&ConventionalCommits{}).Calculate(commits).(ConventionalReport)
+	if unrestricted.Conforming != 4 {
+		t.Errorf("Expected any type to conform without a type list, got %d", unrestricted.Conforming)
+	}
+}
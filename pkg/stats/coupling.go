@@ -0,0 +1,162 @@
+package stats
+
+import (
+	"path"
+	"sort"
+
+	"git-watcher/pkg/analyzer"
+)
+
+// TemporalCoupling finds files that tend to change in the same commits.
+// Commits touching more than MaxFiles files (mass renames, reformatting) are
+// ignored because they couple everything with everything.
+type TemporalCoupling struct {
+	Top        int
+	MinSupport int
+	MaxFiles   int
+}
+
+// CoupledPair describes two files changed together. Support is the number of
+// shared commits, the confidences are the share of each file's changes that
+// also touched the other file, and Degree is the share of commits touching
+// either file that touched both.
+type CoupledPair struct {
+	FileA          string  `json:"file_a"`
+	FileB          string  `json:"file_b"`
+	Support        int     `json:"support"`
+	ConfidenceAB   float64 `json:"confidence_a_to_b"`
+	ConfidenceBA   float64 `json:"confidence_b_to_a"`
+	Degree         float64 `json:"degree"`
+	CrossDirectory bool    `json:"cross_directory"`
+}
+
+type CouplingPartner struct {
+	File       string  `json:"file"`
+	Support    int     `json:"support"`
+	Confidence float64 `json:"confidence"`
+	Degree     float64 `json:"degree"`
+}
+
+type CouplingReport struct {
+	Pairs []CoupledPair `json:"pairs"`
+
+	partners map[string][]CouplingPartner
+}
+
+// Files lists every file with at least one coupled partner, strongest
+// coupling first.
+func (r CouplingReport) Files() []string {
+	files := make([]string, 0, len(r.partners))
+	for file := range r.partners {
+		files = append(files, file)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		a, b := r.partners[files[i]][0], r.partners[files[j]][0]
+		if a.Degree != b.Degree {
+			return a.Degree > b.Degree
+		}
+		if a.Support != b.Support {
+			return a.Support > b.Support
+		}
+		return files[i] < files[j]
+	})
+	return files
+}
+
+// Partners returns the files most coupled with file, strongest first.
+func (r CouplingReport) Partners(file string) []CouplingPartner {
+	return r.partners[file]
+}
+
+func (t *TemporalCoupling) Name() string {
+	return "temporal_coupling"
+}
+
+func (t *TemporalCoupling) Calculate(commits []analyzer.CommitInfo) interface{} {
+	type pairKey struct{ a, b string }
+
+	changes := make(map[string]int)
+	shared := make(map[pairKey]int)
+	for _, commit := range commits {
+		for _, f := range commit.Files {
+			changes[f.Path]++
+		}
+		if len(commit.Files) < 2 || (t.MaxFiles > 0 && len(commit.Files) > t.MaxFiles) {
+			continue
+		}
+		files := make([]string, 0, len(commit.Files))
+		for _, f := range commit.Files {
+			files = append(files, f.Path)
+		}
+		sort.Strings(files)
+		for i := 0; i < len(files); i++ {
+			for j := i + 1; j < len(files); j++ {
+				if files[i] != files[j] {
+					shared[pairKey{files[i], files[j]}]++
+				}
+			}
+		}
+	}
+
+	minSupport := t.MinSupport
+	if minSupport < 1 {
+		minSupport = 1
+	}
+
+	report := CouplingReport{
+		Pairs:    []CoupledPair{},
+		partners: make(map[string][]CouplingPartner),
+	}
+	for key, support := range shared {
+		if support < minSupport {
+			continue
+		}
+		changesA, changesB := changes[key.a], changes[key.b]
+		pair := CoupledPair{
+			FileA:          key.a,
+			FileB:          key.b,
+			Support:        support,
+			ConfidenceAB:   float64(support) / float64(changesA),
+			ConfidenceBA:   float64(support) / float64(changesB),
+			Degree:         float64(support) / float64(changesA+changesB-support),
+			CrossDirectory: path.Dir(key.a) != path.Dir(key.b),
+		}
+		report.Pairs = append(report.Pairs, pair)
+		report.partners[key.a] = append(report.partners[key.a], CouplingPartner{
+			File: key.b, Support: support, Confidence: pair.ConfidenceAB, Degree: pair.Degree,
+		})
+		report.partners[key.b] = append(report.partners[key.b], CouplingPartner{
+			File: key.a, Support: support, Confidence: pair.ConfidenceBA, Degree: pair.Degree,
+		})
+	}
+
+	sort.Slice(report.Pairs, func(i, j int) bool {
+		a, b := report.Pairs[i], report.Pairs[j]
+		if a.Degree != b.Degree {
+			return a.Degree > b.Degree
+		}
+		if a.Support != b.Support {
+			return a.Support > b.Support
+		}
+		if a.FileA != b.FileA {
+			return a.FileA < b.FileA
+		}
+		return a.FileB < b.FileB
+	})
+	for _, partners := range report.partners {
+		sort.Slice(partners, func(i, j int) bool {
+			if partners[i].Degree != partners[j].Degree {
+				return partners[i].Degree > partners[j].Degree
+			}
+			if partners[i].Support != partners[j].Support {
+				return partners[i].Support > partners[j].Support
+			}
+			return partners[i].File < partners[j].File
+		})
+	}
+
+	if t.Top > 0 && len(report.Pairs) > t.Top {
+		report.Pairs = report.Pairs[:t.Top]
+	}
+	return report
+}
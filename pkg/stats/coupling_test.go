@@ -0,0 +1,56 @@
+package stats
+
+import (
+	"testing"
+
+	"git-watcher/pkg/analyzer"
+)
+
+func changed(paths ...string) []analyzer.FileChange {
+	files := make([]analyzer.FileChange, 0, len(paths))
+	for _, p := range paths {
+		files = append(files, analyzer.FileChange{Path: p, Additions: 1})
+	}
+	return files
+}
+
+func TestTemporalCoupling(t *testing.T) {
+	commits := []analyzer.CommitInfo{
+		{Author: "Alice", Files: changed("api/handler.go", "web/client.ts")},
+		{Author: "Bob", Files: changed("api/handler.go", "web/client.ts", "README.md")},
+		{Author: "Alice", Files: changed("api/handler.go")},
+		{Author: "Alice", Files: changed("README.md", "docs/guide.md")},
+		{Author: "Carol", Files: changed("a.go", "b.go", "c.go", "d.go")},
+	}
+
+	stat := &TemporalCoupling{MinSupport: 2, MaxFiles: 3}
+	result := stat.Calculate(commits).(CouplingReport)
+
+	if len(result.Pairs) != 1 {
+		t.Fatalf("Expected 1 coupled pair, got %d: %+v", len(result.Pairs), result.Pairs)
+	}
+	pair := result.Pairs[0]
+	if pair.FileA != "api/handler.go" || pair.FileB != "web/client.ts" {
+		t.Errorf("Unexpected pair: %+v", pair)
+	}
+	if pair.Support != 2 {
+		t.Errorf("Expected support 2, got %d", pair.Support)
+	}
+	if pair.ConfidenceBA != 1 {
+		t.Errorf("Expected client.ts to always change with handler.go, got %f", pair.ConfidenceBA)
+	}
+	if pair.Degree < 0.66 || pair.Degree > 0.67 {
+		t.Errorf("Expected degree 2/3, got %f", pair.Degree)
+	}
+	if !pair.CrossDirectory {
+		t.Errorf("Expected pair to be marked as cross-directory")
+	}
+
+	partners := result.Partners("web/client.ts")
+	if len(partners) != 1 || partners[0].File != "api/handler.go" {
+		t.Errorf("Unexpected partners for web/client.ts: %+v", partners)
+	}
+	if files := result.Files(); len(files) != 2 {
+		t.Errorf("Expected 2 coupled files, got %v", files)
+	}
+}
@@ -0,0 +1,55 @@
+package stats
+
+import (
+	"time"
+
+	"git-watcher/pkg/analyzer"
+)
+
+// HeatmapDays names the rows of a Heatmap matrix, Monday first.
+var HeatmapDays = [7]string{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"}
+
+// WeekdayHourHeatmap counts commits per weekday and hour of day, the
+// "punch card" view combining the hourly and weekend statistics.
+type WeekdayHourHeatmap struct {
+	ByAuthor bool
+}
+
+type Heatmap struct {
+	Days    [7]string              `json:"days"`
+	Matrix  [7][24]int             `json:"matrix"`
+	Max     int                    `json:"max"`
+	Authors map[string]*[7][24]int `json:"authors,omitempty"`
+}
+
+func (w *WeekdayHourHeatmap) Name() string {
+	return "weekday_hour_heatmap"
+}
+
+func (w *WeekdayHourHeatmap) Calculate(commits []analyzer.CommitInfo) interface{} {
+	heatmap := Heatmap{Days: HeatmapDays}
+	if w.ByAuthor {
+		heatmap.Authors = make(map[string]*[7][24]int)
+	}
+
+	for _, commit := range commits {
+		day, hour := heatmapRow(commit.Date.Weekday()), commit.Date.Hour()
+		heatmap.Matrix[day][hour]++
+		if heatmap.Matrix[day][hour] > heatmap.Max {
+			heatmap.Max = heatmap.Matrix[day][hour]
+		}
+		if w.ByAuthor {
+			m := heatmap.Authors[commit.Author]
+			if m == nil {
+				m = &[7][24]int{}
+				heatmap.Authors[commit.Author] = m
+			}
+			m[day][hour]++
+		}
+	}
+	return heatmap
+}
+
+func heatmapRow(day time.Weekday) int {
+	return (int(day) + 6) % 7
+}
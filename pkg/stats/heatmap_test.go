@@ -0,0 +1,32 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"git-watcher/pkg/analyzer"
+)
+
+func TestWeekdayHourHeatmap(t *testing.T) {
+	commits := []analyzer.CommitInfo{
+		{Author: "Alice", Date: time.Date(2024, 7, 28, 23, 10, 0, 0, time.UTC)}, // Sunday 23:00
+		{Author: "Alice", Date: time.Date(2024, 7, 28, 23, 40, 0, 0, time.UTC)}, // Sunday 23:00
+		{Author: "Bob", Date: time.Date(2024, 7, 29, 9, 0, 0, 0, time.UTC)},     // Monday 09:00
+	}
+
+	stat := &WeekdayHourHeatmap{ByAuthor: true}
+	result := stat.Calculate(commits).(Heatmap)
+
+	if result.Matrix[6][23] != 2 {
+		t.Errorf("Expected 2 commits on Sunday at 23:00, got %d", result.Matrix[6][23])
+	}
+	if result.Matrix[0][9] != 1 {
+		t.Errorf("Expected 1 commit on Monday at 09:00, got %d", result.Matrix[0][9])
+	}
+	if result.Max != 2 {
+		t.Errorf("Expected max of 2, got %d", result.Max)
+	}
+	if result.Authors["Bob"][0][9] != 1 || result.Authors["Bob"][6][23] != 0 {
+		t.Errorf("Unexpected heatmap for Bob: %v", result.Authors["Bob"])
+	}
+}
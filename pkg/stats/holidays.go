@@ -0,0 +1,72 @@
+package stats
+
+import (
+	"sort"
+
+	"git-watcher/pkg/analyzer"
+	"git-watcher/pkg/policy"
+)
+
+// HolidayCommits counts commits made on the public holidays of each author's
+// policy, like WeekendCommits does for days off.
+type HolidayCommits struct {
+	Policies *policy.Set
+}
+
+type HolidayCommitCount struct {
+	Date    string         `json:"date"`
+	Name    string         `json:"name"`
+	Region  string         `json:"region,omitempty"`
+	Commits int            `json:"commits"`
+	Authors map[string]int `json:"authors"`
+}
+
+func (h *HolidayCommits) Name() string {
+	return "holiday_commits"
+}
+
+func (h *HolidayCommits) Calculate(commits []analyzer.CommitInfo) interface{} {
+	holidayCount := 0
+	holidayAuthors := make(map[string]int)
+	byDate := make(map[string]*HolidayCommitCount)
+
+	for _, commit := range commits {
+		holiday, ok := h.Policies.For(commit.Author, commit.Email).Holiday(commit.Date)
+		if !ok {
+			continue
+		}
+		holidayCount++
+		holidayAuthors[commit.Author]++
+		// authors of different regions may have different holidays on a date
+		key := holiday.Date + "\x00" + holiday.Name
+		day := byDate[key]
+		if day == nil {
+			day = &HolidayCommitCount{
+				Date:    holiday.Date,
+				Name:    holiday.Name,
+				Region:  holiday.Region,
+				Authors: make(map[string]int),
+			}
+			byDate[key] = day
+		}
+		day.Commits++
+		day.Authors[commit.Author]++
+	}
+
+	holidays := make([]HolidayCommitCount, 0, len(byDate))
+	for _, day := range byDate {
+		holidays = append(holidays, *day)
+	}
+	sort.Slice(holidays, func(i, j int) bool {
+		if holidays[i].Date != holidays[j].Date {
+			return holidays[i].Date < holidays[j].Date
+		}
+		return holidays[i].Name < holidays[j].Name
+	})
+
+	return map[string]interface{}{
+		"total":    holidayCount,
+		"authors":  holidayAuthors,
+		"holidays": holidays,
+	}
+}
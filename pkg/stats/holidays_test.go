@@ -0,0 +1,72 @@
+package stats
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"git-watcher/pkg/analyzer"
+	"git-watcher/pkg/policy"
+)
+
+func TestHolidayCommits(t *testing.T) {
+	dir := t.TempDir()
+	cn := filepath.Join(dir, "cn.yaml")
+	us := filepath.Join(dir, "us.yaml")
+	if err := os.WriteFile(cn, []byte("holidays:\n  - date: 2024-10-01\n    end: 2024-10-07\n    name: National Day\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(us, []byte("holidays:\n  - date: 2024-07-04\n    name: Independence Day\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	teams := filepath.Join(dir, "policy.yaml")
+	if err := os.WriteFile(teams, []byte("default:\n  region: CN\nteams:\n  us:\n    members: [Bob]\n    region: US\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	policies, err := policy.Load(teams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := policies.LoadHolidays([]string{"CN=" + cn, "US=" + us}); err != nil {
+		t.Fatalf("Failed to load calendars: %v", err)
+	}
+
+	commits := []analyzer.CommitInfo{
+		{Author: "Alice", Date: time.Date(2024, 10, 2, 10, 0, 0, 0, time.UTC)},
+		{Author: "Bob", Date: time.Date(2024, 10, 2, 15, 0, 0, 0, time.UTC)}, // not a US holiday
+		{Author: "Bob", Date: time.Date(2024, 7, 4, 9, 0, 0, 0, time.UTC)},
+		{Author: "Alice", Date: time.Date(2024, 7, 4, 9, 0, 0, 0, time.UTC)}, // not a CN holiday
+		{Author: "Bob", Date: time.Date(2024, 7, 5, 9, 0, 0, 0, time.UTC)},   // ordinary workday
+	}
+
+	stat := &HolidayCommits{Policies: policies}
+	result := stat.Calculate(commits).(map[string]interface{})
+
+	if result["total"].(int) != 2 {
+		t.Errorf("Expected 2 holiday commits, got %v", result["total"])
+	}
+	authors := result["authors"].(map[string]int)
+	if authors["Alice"] != 1 || authors["Bob"] != 1 {
+		t.Errorf("Unexpected holiday authors: %v", authors)
+	}
+	holidays := result["holidays"].([]HolidayCommitCount)
+	if len(holidays) != 2 {
+		t.Fatalf("Expected 2 holidays with commits, got %d", len(holidays))
+	}
+	if holidays[0].Name != "Independence Day" || holidays[0].Region != "US" || holidays[0].Commits != 1 {
+		t.Errorf("Unexpected first holiday: %+v", holidays[0])
+	}
+	if holidays[1].Name != "National Day" || holidays[1].Region != "CN" || holidays[1].Commits != 1 {
+		t.Errorf("Unexpected second holiday: %+v", holidays[1])
+	}
+
+	if err := policy.NewSet().LoadHolidays([]string{"DE=" + us}); err == nil {
+		t.Errorf("Expected an error for a region without policies")
+	}
+
+	empty := (&HolidayCommits{}).Calculate(commits).(map[string]interface{})
+	if empty["total"].(int) != 0 {
+		t.Errorf("Expected no holiday commits without calendars, got %v", empty["total"])
+	}
+}
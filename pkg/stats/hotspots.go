@@ -0,0 +1,170 @@
+package stats
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"git-watcher/pkg/analyzer"
+)
+
+// FileHotspots ranks files by how often they change, how many lines churn
+// through them and how many people touch them.
+type FileHotspots struct {
+	Top    int
+	Window time.Duration
+}
+
+type FileHotspot struct {
+	Path    string         `json:"path"`
+	Changes int            `json:"changes"`
+	Churn   int64          `json:"churn"`
+	Authors int            `json:"authors"`
+	Score   float64        `json:"score"`
+	Trend   map[string]int `json:"trend"`
+}
+
+type HotspotReport struct {
+	Since time.Time     `json:"since"`
+	Until time.Time     `json:"until"`
+	Files []FileHotspot `json:"files"`
+}
+
+func (f *FileHotspots) Name() string {
+	return "hotspots"
+}
+
+func (f *FileHotspots) Calculate(commits []analyzer.CommitInfo) interface{} {
+	report := HotspotReport{Files: []FileHotspot{}}
+	if len(commits) == 0 {
+		return report
+	}
+
+	until := commits[0].Date
+	for _, commit := range commits {
+		if commit.Date.After(until) {
+			until = commit.Date
+		}
+	}
+	var cutoff time.Time
+	if f.Window > 0 {
+		cutoff = until.Add(-f.Window)
+	}
+
+	type fileAcc struct {
+		hotspot FileHotspot
+		authors map[string]bool
+	}
+	files := make(map[string]*fileAcc)
+	since := until
+	for _, commit := range commits {
+		if commit.Date.Before(cutoff) {
+			continue
+		}
+		if commit.Date.Before(since) {
+			since = commit.Date
+		}
+		month := commit.Date.Format("2006-01")
+		for _, change := range commit.Files {
+			acc := files[change.Path]
+			if acc == nil {
+				acc = &fileAcc{
+					hotspot: FileHotspot{Path: change.Path, Trend: make(map[string]int)},
+					authors: make(map[string]bool),
+				}
+				files[change.Path] = acc
+			}
+			acc.hotspot.Changes++
+			acc.hotspot.Churn += int64(change.Additions + change.Deletions)
+			acc.hotspot.Trend[month]++
+			acc.authors[commit.Author] = true
+		}
+	}
+	report.Since = since
+	report.Until = until
+
+	var maxChanges, maxAuthors int
+	var maxChurn int64
+	for _, acc := range files {
+		acc.hotspot.Authors = len(acc.authors)
+		if acc.hotspot.Changes > maxChanges {
+			maxChanges = acc.hotspot.Changes
+		}
+		if acc.hotspot.Churn > maxChurn {
+			maxChurn = acc.hotspot.Churn
+		}
+		if acc.hotspot.Authors > maxAuthors {
+			maxAuthors = acc.hotspot.Authors
+		}
+	}
+
+	for _, acc := range files {
+		h := acc.hotspot
+		h.Score = 100 * (0.5*ratio(float64(h.Changes), float64(maxChanges)) +
+			0.3*ratio(math.Log1p(float64(h.Churn)), math.Log1p(float64(maxChurn))) +
+			0.2*ratio(float64(h.Authors), float64(maxAuthors)))
+		h.Score = math.Round(h.Score*100) / 100
+		report.Files = append(report.Files, h)
+	}
+
+	SortHotspots(report.Files, HotspotSortScore)
+	if f.Top > 0 && len(report.Files) > f.Top {
+		report.Files = report.Files[:f.Top]
+	}
+	return report
+}
+
+type HotspotSortKey int
+
+const (
+	HotspotSortScore HotspotSortKey = iota
+	HotspotSortChanges
+	HotspotSortChurn
+	HotspotSortAuthors
+)
+
+func (k HotspotSortKey) String() string {
+	switch k {
+	case HotspotSortChanges:
+		return "changes"
+	case HotspotSortChurn:
+		return "churn"
+	case HotspotSortAuthors:
+		return "authors"
+	default:
+		return "score"
+	}
+}
+
+// SortHotspots orders files by the given key, highest first, breaking ties
+// by score and then by path.
+func SortHotspots(files []FileHotspot, key HotspotSortKey) {
+	value := func(h FileHotspot) float64 {
+		switch key {
+		case HotspotSortChanges:
+			return float64(h.Changes)
+		case HotspotSortChurn:
+			return float64(h.Churn)
+		case HotspotSortAuthors:
+			return float64(h.Authors)
+		default:
+			return h.Score
+		}
+	}
+	sort.Slice(files, func(i, j int) bool {
+		if vi, vj := value(files[i]), value(files[j]); vi != vj {
+			return vi > vj
+		}
+		if files[i].Score != files[j].Score {
+			return files[i].Score > files[j].Score
+		}
+		return files[i].Path < files[j].Path
+	})
+}
+
+func ratio(value, max float64) float64 {
+	if max <= 0 {
+		return 0
+	}
+	return value / max
+}
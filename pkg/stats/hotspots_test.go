@@ -0,0 +1,85 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"git-watcher/pkg/analyzer"
+)
+
+func TestFileHotspots(t *testing.T) {
+	commits := []analyzer.CommitInfo{
+		{Author: "Alice", Date: time.Date(2024, 1, 10, 10, 0, 0, 0, time.UTC), Files: []analyzer.FileChange{
+			{Path: "main.go", Additions: 10, Deletions: 2},
+			{Path: "README.md", Additions: 1},
+		}},
+		{Author: "Bob", Date: time.Date(2024, 2, 5, 10, 0, 0, 0, time.UTC), Files: []analyzer.FileChange{
+			{Path: "main.go", Additions: 5, Deletions: 5},
+		}},
+		{Author: "Alice", Date: time.Date(2024, 2, 6, 10, 0, 0, 0, time.UTC), Files: []analyzer.FileChange{
+			{Path: "main.go", Additions: 1, Deletions: 1},
+			{Path: "util.go", Additions: 100},
+		}},
+	}
+
+	stat := &FileHotspots{}
+	result := stat.Calculate(commits).(HotspotReport)
+
+	if len(result.Files) != 3 {
+		t.Fatalf("Expected 3 files, got %d", len(result.Files))
+	}
+	top := result.Files[0]
+	if top.Path != "main.go" {
+		t.Errorf("Expected main.go to be the top hotspot, got %s", top.Path)
+	}
+	if top.Changes != 3 || top.Churn != 24 || top.Authors != 2 {
+		t.Errorf("Unexpected main.go hotspot: %+v", top)
+	}
+	if top.Trend["2024-01"] != 1 || top.Trend["2024-02"] != 2 {
+		t.Errorf("Unexpected main.go trend: %v", top.Trend)
+	}
+}
+
+func TestFileHotspotsWindowAndTop(t *testing.T) {
+	commits := []analyzer.CommitInfo{
+		{Author: "Alice", Date: time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC), Files: []analyzer.FileChange{
+			{Path: "old.go", Additions: 10},
+		}},
+		{Author: "Alice", Date: time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC), Files: []analyzer.FileChange{
+			{Path: "a.go", Additions: 10},
+			{Path: "b.go", Additions: 1},
+		}},
+		{Author: "Bob", Date: time.Date(2024, 3, 2, 10, 0, 0, 0, time.UTC), Files: []analyzer.FileChange{
+			{Path: "a.go", Additions: 3},
+		}},
+	}
+
+	stat := &FileHotspots{Top: 1, Window: 30 * 24 * time.Hour}
+	result := stat.Calculate(commits).(HotspotReport)
+
+	if len(result.Files) != 1 {
+		t.Fatalf("Expected 1 file, got %d", len(result.Files))
+	}
+	if result.Files[0].Path != "a.go" {
+		t.Errorf("Expected a.go to be the top hotspot, got %s", result.Files[0].Path)
+	}
+	if !result.Since.Equal(commits[1].Date) {
+		t.Errorf("Expected window to start at %v, got %v", commits[1].Date, result.Since)
+	}
+}
+
+func TestSortHotspots(t *testing.T) {
+	files := []FileHotspot{
+		{Path: "a.go", Changes: 5, Churn: 10, Score: 50},
+		{Path: "b.go", Changes: 2, Churn: 500, Score: 40},
+	}
+
+	SortHotspots(files, HotspotSortChurn)
+	if files[0].Path != "b.go" {
+		t.Errorf("Expected b.go first when sorting by churn, got %s", files[0].Path)
+	}
+	SortHotspots(files, HotspotSortChanges)
+	if files[0].Path != "a.go" {
+		t.Errorf("Expected a.go first when sorting by changes, got %s", files[0].Path)
+	}
+}
@@ -0,0 +1,292 @@
+package stats
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"git-watcher/pkg/analyzer"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Message rules scored by MessageQuality.
+const (
+	RuleSubjectLength  = "subject_length"
+	RuleImperative     = "imperative"
+	RuleBlankLine      = "blank_line"
+	RuleBody           = "body"
+	RuleBanned         = "banned"
+	RuleTrailingPeriod = "trailing_period"
+)
+
+// MessageRules configure MessageQuality. Each broken rule costs its weight
+// from a perfect score of 100; a zero weight disables the rule.
+type MessageRules struct {
+	SubjectMinLength int `yaml:"subject_min_length"`
+	SubjectMaxLength int `yaml:"subject_max_length"`
+	// BodyOverLines requires a body on commits changing more lines.
+	BodyOverLines int64          `yaml:"body_over_lines"`
+	Banned        []string       `yaml:"banned"`
+	Weights       map[string]int `yaml:"weights"`
+
+	banned []*regexp.Regexp
+}
+
+func DefaultMessageRules() *MessageRules {
+	rules := &MessageRules{
+		SubjectMinLength: 10,
+		SubjectMaxLength: 72,
+		BodyOverLines:    100,
+		Banned: []string{
+			`(?i)^wip\b`,
+			`(?i)^(asdf+|qwe+r?t?y?|test|tmp|temp|update|updates|changes?|stuff|misc|fix|fixes|\.+|-+)$`,
+		},
+		Weights: map[string]int{
+			RuleSubjectLength:  20,
+			RuleImperative:     20,
+			RuleBlankLine:      15,
+			RuleBody:           15,
+			RuleBanned:         40,
+			RuleTrailingPeriod: 5,
+		},
+	}
+	_ = rules.compile()
+	return rules
+}
+
+// LoadMessageRules reads rules from YAML, keeping defaults for anything the
+// file leaves out:
+//
+//	subject_max_length: 50
+//	body_over_lines: 200
+//	banned: ["(?i)^wip\\b"]
+//	weights:
+//	  imperative: 0
+func LoadMessageRules(path string) (*MessageRules, error) {
+	rules := DefaultMessageRules()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read message rules: %w", err)
+	}
+	weights := rules.Weights
+	rules.Weights = nil
+	if err := yaml.Unmarshal(data, rules); err != nil {
+		return nil, fmt.Errorf("failed to parse message rules %s: %w", path, err)
+	}
+	for rule, weight := range rules.Weights {
+		if _, ok := weights[rule]; !ok {
+			return nil, fmt.Errorf("unknown message rule %q in %s", rule, path)
+		}
+		weights[rule] = weight
+	}
+	rules.Weights = weights
+	if err := rules.compile(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return rules, nil
+}
+
+func (r *MessageRules) compile() error {
+	r.banned = r.banned[:0]
+	for _, pattern := range r.Banned {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid banned message pattern %q: %w", pattern, err)
+		}
+		r.banned = append(r.banned, re)
+	}
+	return nil
+}
+
+// Check returns the rules commit's message breaks.
+func (r *MessageRules) Check(commit analyzer.CommitInfo) []string {
+	lines := strings.Split(strings.ReplaceAll(strings.TrimSpace(commit.Message), "\r\n", "\n"), "\n")
+	subject := strings.TrimSpace(lines[0])
+	var broken []string
+	fail := func(rule string) {
+		if r.Weights[rule] > 0 {
+			broken = append(broken, rule)
+		}
+	}
+
+	if n := len([]rune(subject)); n < r.SubjectMinLength || (r.SubjectMaxLength > 0 && n > r.SubjectMaxLength) {
+		fail(RuleSubjectLength)
+	}
+	description := subject
+	if cc := commit.Conventional; cc != nil {
+		description = cc.Description
+	}
+	if !isImperative(description) {
+		fail(RuleImperative)
+	}
+	if len(lines) > 1 && strings.TrimSpace(lines[1]) != "" {
+		fail(RuleBlankLine)
+	}
+	hasBody := false
+	for _, line := range lines[1:] {
+		if strings.TrimSpace(line) != "" {
+			hasBody = true
+			break
+		}
+	}
+	if !hasBody && r.BodyOverLines > 0 && commit.LineCount > r.BodyOverLines {
+		fail(RuleBody)
+	}
+	for _, re := range r.banned {
+		if re.MatchString(subject) {
+			fail(RuleBanned)
+			break
+		}
+	}
+	if strings.HasSuffix(subject, ".") && !strings.HasSuffix(subject, "...") {
+		fail(RuleTrailingPeriod)
+	}
+	return broken
+}
+
+// Score returns 100 less the weight of every broken rule, never below 0.
+func (r *MessageRules) Score(broken []string) int {
+	score := 100
+	for _, rule := range broken {
+		score -= r.Weights[rule]
+	}
+	if score < 0 {
+		score = 0
+	}
+	return score
+}
+
+var (
+	// verbs whose -ed/-ing/-s forms give away a non-imperative subject
+	// but whose base form does not follow the -ed/-ing rule
+	imperativeExceptions = map[string]bool{
+		"need": true, "embed": true, "feed": true, "seed": true, "speed": true, "shed": true,
+		"proceed": true, "exceed": true, "succeed": true, "bring": true, "string": true, "ping": true,
+	}
+	commonVerbs = map[string]bool{
+		"add": true, "fix": true, "update": true, "remove": true, "change": true, "make": true,
+		"use": true, "improve": true, "implement": true, "refactor": true, "move": true,
+		"rename": true, "introduce": true, "allow": true, "support": true, "handle": true,
+		"ensure": true, "prevent": true, "bump": true, "clean": true, "create": true,
+		"delete": true, "drop": true, "enable": true, "disable": true, "merge": true,
+		"replace": true, "set": true, "simplify": true, "switch": true, "upgrade": true,
+	}
+)
+
+// isImperative guesses whether a subject starts with an imperative verb,
+// rejecting "Added ...", "Adding ..." and "Adds ...".
+func isImperative(subject string) bool {
+	fields := strings.Fields(subject)
+	if len(fields) == 0 {
+		return false
+	}
+	word := strings.ToLower(strings.Trim(fields[0], ":,.!"))
+	if imperativeExceptions[word] {
+		return true
+	}
+	if strings.HasSuffix(word, "ed") || strings.HasSuffix(word, "ing") {
+		return false
+	}
+	if strings.HasSuffix(word, "es") && commonVerbs[strings.TrimSuffix(word, "es")] {
+		return false
+	}
+	if strings.HasSuffix(word, "s") && commonVerbs[strings.TrimSuffix(word, "s")] {
+		return false
+	}
+	return true
+}
+
+// MessageQuality scores commit messages against Rules and ranks the worst.
+// Merge commits and reverts written by git are not scored.
+type MessageQuality struct {
+	Rules *MessageRules
+	Top   int
+}
+
+type AuthorMessageQuality struct {
+	Commits    int            `json:"commits"`
+	Average    float64        `json:"average"`
+	Violations map[string]int `json:"violations"`
+}
+
+type MessageOffender struct {
+	Hash       string   `json:"hash"`
+	Author     string   `json:"author"`
+	Subject    string   `json:"subject"`
+	Score      int      `json:"score"`
+	Violations []string `json:"violations"`
+}
+
+type MessageQualityReport struct {
+	Commits    int                              `json:"commits"`
+	Average    float64                          `json:"average"`
+	Violations map[string]int                   `json:"violations"`
+	Authors    map[string]*AuthorMessageQuality `json:"authors"`
+	Worst      []MessageOffender                `json:"worst"`
+}
+
+func (m *MessageQuality) Name() string {
+	return "message_quality"
+}
+
+func (m *MessageQuality) Calculate(commits []analyzer.CommitInfo) interface{} {
+	rules := m.Rules
+	if rules == nil {
+		rules = DefaultMessageRules()
+	}
+	report := MessageQualityReport{
+		Violations: make(map[string]int),
+		Authors:    make(map[string]*AuthorMessageQuality),
+		Worst:      []MessageOffender{},
+	}
+
+	var total int
+	for _, commit := range commits {
+		if strings.HasPrefix(commit.Message, "Merge ") || (commit.FollowUp != nil && commit.FollowUp.Kind == analyzer.FollowUpRevert) {
+			continue
+		}
+		broken := rules.Check(commit)
+		score := rules.Score(broken)
+
+		author := report.Authors[commit.Author]
+		if author == nil {
+			author = &AuthorMessageQuality{Violations: make(map[string]int)}
+			report.Authors[commit.Author] = author
+		}
+		author.Commits++
+		author.Average += float64(score)
+		report.Commits++
+		total += score
+		for _, rule := range broken {
+			author.Violations[rule]++
+			report.Violations[rule]++
+		}
+		if len(broken) > 0 {
+			report.Worst = append(report.Worst, MessageOffender{
+				Hash:       commit.Hash,
+				Author:     commit.Author,
+				Subject:    strings.SplitN(strings.TrimSpace(commit.Message), "\n", 2)[0],
+				Score:      score,
+				Violations: broken,
+			})
+		}
+	}
+
+	for _, author := range report.Authors {
+		author.Average /= float64(author.Commits)
+	}
+	report.Average = ratio(float64(total), float64(report.Commits))
+	sort.Slice(report.Worst, func(i, j int) bool {
+		if report.Worst[i].Score != report.Worst[j].Score {
+			return report.Worst[i].Score < report.Worst[j].Score
+		}
+		return report.Worst[i].Hash < report.Worst[j].Hash
+	})
+	if m.Top > 0 && len(report.Worst) > m.Top {
+		report.Worst = report.Worst[:m.Top]
+	}
+	return report
+}
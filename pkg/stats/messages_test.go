@@ -0,0 +1,81 @@
+package stats
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"git-watcher/pkg/analyzer"
+)
+
+func TestMessageRulesCheck(t *testing.T) {
+	rules := DefaultMessageRules()
+	for _, tc := range []struct {
+		message string
+		lines   int64
+		want    []string
+	}{
+		{"Add retry to the payment client", 10, nil},
+		{"feat(api): add retry to the payment client", 10, nil},
+		{"Added retry to the payment client.", 10, []string{RuleImperative, RuleTrailingPeriod}},
+		{"Fixes crash when the cache is empty\nsee #12", 10, []string{RuleImperative, RuleBlankLine}},
+		{"Rewrite the scheduler from scratch", 500, []string{RuleBody}},
+		{"Rewrite the scheduler from scratch\n\nThe old one leaked goroutines.", 500, nil},
+		{"wip", 10, []string{RuleSubjectLength, RuleBanned}},
+		{"asdf", 10, []string{RuleSubjectLength, RuleBanned}},
+	} {
+		commit := analyzer.CommitInfo{Message: tc.message, LineCount: tc.lines, Conventional: analyzer.ParseConventionalCommit(tc.message)}
+		if got := rules.Check(commit); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("Check(%q) = %v, want %v", tc.message, got, tc.want)
+		}
+	}
+	if score := rules.Score([]string{RuleSubjectLength, RuleBanned}); score != 40 {
+		t.Errorf("Expected a score of 40, got %d", score)
+	}
+}
+
+func TestLoadMessageRules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	if err := os.WriteFile(path, []byte("subject_max_length: 20\nbanned: ['^tmp$']\nweights:\n  imperative: 0\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	rules, err := LoadMessageRules(path)
+	if err != nil {
+		t.Fatalf("Failed to load rules: %v", err)
+	}
+	got := rules.Check(analyzer.CommitInfo{Message: "Added a rather long subject line"})
+	if !reflect.DeepEqual(got, []string{RuleSubjectLength}) {
+		t.Errorf("Expected only the length rule to break, got %v", got)
+	}
+	if rules.Weights[RuleBanned] != 40 || len(rules.Banned) != 1 {
+		t.Errorf("Expected defaults to be kept and banned patterns replaced, got %+v", rules)
+	}
+
+	if err := os.WriteFile(path, []byte("weights:\n  emoji: 10\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadMessageRules(path); err == nil {
+		t.Errorf("Expected an error for an unknown rule")
+	}
+}
+
+func TestMessageQuality(t *testing.T) {
+	commits := []analyzer.CommitInfo{
+		{Hash: "a", Author: "Alice", Message: "Add retry to the payment client"},
+		{Hash: "b", Author: "Alice", Message: "wip"},
+		{Hash: "c", Author: "Bob", Message: "Merge branch 'main'"},
+		{Hash: "d", Author: "Bob", Message: "Updated the readme file"},
+	}
+	report := (&MessageQuality{Top: 1}).Calculate(commits).(MessageQualityReport)
+
+	if report.Commits != 3 || report.Average != 220.0/3 {
+		t.Errorf("Unexpected totals: %d commits, average %v", report.Commits, report.Average)
+	}
+	if alice := report.Authors["Alice"]; alice.Average != 70 || alice.Violations[RuleBanned] != 1 {
+		t.Errorf("Unexpected quality for Alice: %+v", alice)
+	}
+	if len(report.Worst) != 1 || report.Worst[0].Hash != "b" || report.Worst[0].Score != 40 {
+		t.Errorf("Unexpected worst offenders: %+v", report.Worst)
+	}
+}
@@ -0,0 +1,53 @@
+package stats
+
+import (
+	"fmt"
+	"time"
+
+	"git-watcher/pkg/analyzer"
+	"git-watcher/pkg/policy"
+)
+
+// Overtime counts each author's commits outside the working hours of their
+// policy, per ISO week.
+type Overtime struct {
+	Policies *policy.Set
+}
+
+type AuthorOvertime struct {
+	Policy     string         `json:"policy"`
+	Commits    int            `json:"commits"`
+	OutOfHours int            `json:"out_of_hours"`
+	Weeks      map[string]int `json:"weeks"`
+}
+
+type OvertimeReport struct {
+	Authors map[string]*AuthorOvertime `json:"authors"`
+}
+
+func (o *Overtime) Name() string {
+	return "overtime"
+}
+
+func (o *Overtime) Calculate(commits []analyzer.CommitInfo) interface{} {
+	report := OvertimeReport{Authors: make(map[string]*AuthorOvertime)}
+	for _, commit := range commits {
+		p := o.Policies.For(commit.Author, commit.Email)
+		a := report.Authors[commit.Author]
+		if a == nil {
+			a = &AuthorOvertime{Policy: p.String(), Weeks: make(map[string]int)}
+			report.Authors[commit.Author] = a
+		}
+		a.Commits++
+		if p.IsOutOfHours(commit.Date) {
+			a.OutOfHours++
+			a.Weeks[isoWeek(commit.Date)]++
+		}
+	}
+	return report
+}
+
+func isoWeek(t time.Time) string {
+	year, week := t.ISOWeek()
+	return fmt.Sprintf("%d-W%02d", year, week)
+}
@@ -0,0 +1,34 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"git-watcher/pkg/analyzer"
+)
+
+func TestOvertime(t *testing.T) {
+	commits := []analyzer.CommitInfo{
+		{Author: "Alice", Date: time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)},  // Tuesday 10:00 - working hours
+		{Author: "Alice", Date: time.Date(2024, 1, 2, 20, 0, 0, 0, time.UTC)},  // Tuesday 20:00 - after hours
+		{Author: "Alice", Date: time.Date(2024, 1, 6, 11, 0, 0, 0, time.UTC)},  // Saturday
+		{Author: "Alice", Date: time.Date(2024, 1, 10, 7, 30, 0, 0, time.UTC)}, // next Wednesday 07:30 - before hours
+	}
+
+	stat := &Overtime{}
+	result := stat.Calculate(commits).(OvertimeReport)
+
+	alice := result.Authors["Alice"]
+	if alice == nil {
+		t.Fatalf("Expected an overtime entry for Alice")
+	}
+	if alice.Commits != 4 || alice.OutOfHours != 3 {
+		t.Errorf("Expected 3 of 4 commits out of hours, got %d of %d", alice.OutOfHours, alice.Commits)
+	}
+	if alice.Weeks["2024-W01"] != 2 || alice.Weeks["2024-W02"] != 1 {
+		t.Errorf("Unexpected weekly overtime: %v", alice.Weeks)
+	}
+	if alice.Policy != "default (Mon,Tue,Wed,Thu,Fri 09:00-18:00)" {
+		t.Errorf("Unexpected policy description: %s", alice.Policy)
+	}
+}
@@ -0,0 +1,173 @@
+package stats
+
+import (
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"git-watcher/pkg/analyzer"
+
+	"golang.org/x/mod/semver"
+)
+
+// Releases lists the tagged releases in the history with what went into
+// each: the commits reachable from its tag but not from an earlier release,
+// their authors and changed lines, and the time since the previous release.
+// Pattern, a glob such as "v2.*", and Semver narrow the tags that count as
+// releases; Top limits the authors listed per release.
+type Releases struct {
+	Pattern string
+	Semver  bool
+	Top     int
+}
+
+type ReleaseAuthor struct {
+	Author  string `json:"author"`
+	Commits int    `json:"commits"`
+	Lines   int64  `json:"lines"`
+}
+
+type Release struct {
+	Name              string          `json:"name"`
+	Hash              string          `json:"hash"`
+	Date              string          `json:"date"`
+	DaysSincePrevious float64         `json:"days_since_previous"`
+	Commits           int             `json:"commits"`
+	Lines             int64           `json:"lines"`
+	Authors           int             `json:"authors"`
+	TopAuthors        []ReleaseAuthor `json:"top_authors"`
+}
+
+type ReleaseReport struct {
+	// Releases are ordered newest first.
+	Releases        []Release `json:"releases"`
+	MeanDaysBetween float64   `json:"mean_days_between"`
+	// Unreleased counts the commits not reachable from any release.
+	Unreleased      int   `json:"unreleased"`
+	UnreleasedLines int64 `json:"unreleased_lines"`
+}
+
+func (r *Releases) Name() string {
+	return "releases"
+}
+
+// IsRelease reports whether tag counts as a release under pattern and, when
+// requireSemver is set, semantic versioning with or without a "v" prefix.
+func IsRelease(tag, pattern string, requireSemver bool) bool {
+	if pattern != "" {
+		if ok, _ := path.Match(pattern, tag); !ok {
+			return false
+		}
+	}
+	return !requireSemver || semver.IsValid(canonicalVersion(tag))
+}
+
+func canonicalVersion(tag string) string {
+	if strings.HasPrefix(tag, "v") {
+		return tag
+	}
+	return "v" + tag
+}
+
+func (r *Releases) Calculate(commits []analyzer.CommitInfo) interface{} {
+	report := ReleaseReport{Releases: []Release{}}
+
+	type tagged struct {
+		tag    analyzer.Tag
+		commit int
+	}
+	var tags []tagged
+	byHash := make(map[string]int, len(commits))
+	for i, commit := range commits {
+		byHash[commit.Hash] = i
+		for _, tag := range commit.Tags {
+			if IsRelease(tag.Name, r.Pattern, r.Semver) {
+				tags = append(tags, tagged{tag, i})
+			}
+		}
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		a, b := tags[i].tag, tags[j].tag
+		if !a.Date.Equal(b.Date) {
+			return a.Date.Before(b.Date)
+		}
+		if va, vb := canonicalVersion(a.Name), canonicalVersion(b.Name); semver.IsValid(va) && semver.IsValid(vb) && semver.Compare(va, vb) != 0 {
+			return semver.Compare(va, vb) < 0
+		}
+		return a.Name < b.Name
+	})
+
+	released := make([]bool, len(commits))
+	var previous time.Time
+	var totalDays float64
+	for _, t := range tags {
+		release := Release{
+			Name: t.tag.Name,
+			Hash: commits[t.commit].Hash,
+			Date: t.tag.Date.Format(calendarDateFormat),
+		}
+		if !previous.IsZero() {
+			release.DaysSincePrevious = t.tag.Date.Sub(previous).Hours() / 24
+			totalDays += release.DaysSincePrevious
+		}
+		previous = t.tag.Date
+
+		authors := make(map[string]*ReleaseAuthor)
+		stack := []int{t.commit}
+		for len(stack) > 0 {
+			i := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if released[i] {
+				continue
+			}
+			released[i] = true
+			commit := commits[i]
+			release.Commits++
+			release.Lines += commit.LineCount
+			a := authors[commit.Author]
+			if a == nil {
+				a = &ReleaseAuthor{Author: commit.Author}
+				authors[commit.Author] = a
+			}
+			a.Commits++
+			a.Lines += commit.LineCount
+			for _, parent := range commit.Parents {
+				if p, ok := byHash[parent]; ok && !released[p] {
+					stack = append(stack, p)
+				}
+			}
+		}
+
+		release.Authors = len(authors)
+		release.TopAuthors = make([]ReleaseAuthor, 0, len(authors))
+		for _, a := range authors {
+			release.TopAuthors = append(release.TopAuthors, *a)
+		}
+		sort.Slice(release.TopAuthors, func(i, j int) bool {
+			a, b := release.TopAuthors[i], release.TopAuthors[j]
+			if a.Commits != b.Commits {
+				return a.Commits > b.Commits
+			}
+			return a.Author < b.Author
+		})
+		if r.Top > 0 && len(release.TopAuthors) > r.Top {
+			release.TopAuthors = release.TopAuthors[:r.Top]
+		}
+		report.Releases = append(report.Releases, release)
+	}
+
+	for i, commit := range commits {
+		if !released[i] {
+			report.Unreleased++
+			report.UnreleasedLines += commit.LineCount
+		}
+	}
+	if len(tags) > 1 {
+		report.MeanDaysBetween = totalDays / float64(len(tags)-1)
+	}
+	for i, j := 0, len(report.Releases)-1; i < j; i, j = i+1, j-1 {
+		report.Releases[i], report.Releases[j] = report.Releases[j], report.Releases[i]
+	}
+	return report
+}
@@ -0,0 +1,69 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"git-watcher/pkg/analyzer"
+)
+
+func releaseHistory() []analyzer.CommitInfo {
+	day := func(d int) time.Time { return time.Date(2024, 3, d, 12, 0, 0, 0, time.UTC) }
+	commit := func(hash, author string, d int, lines int64, parents ...string) analyzer.CommitInfo {
+		return analyzer.CommitInfo{Hash: hash, Author: author, Date: day(d), LineCount: lines, Parents: parents}
+	}
+	tag := func(c analyzer.CommitInfo, name string, d int) analyzer.CommitInfo {
+		c.Tags = append(c.Tags, analyzer.Tag{Name: name, Date: day(d)})
+		return c
+	}
+	// a - b - d (v1.1.0) - e
+	//   \ c /
+	return []analyzer.CommitInfo{
+		commit("e", "Alice", 20, 5, "d"),
+		tag(tag(commit("d", "Alice", 10, 1, "b", "c"), "v1.1.0", 11), "nightly", 11),
+		commit("c", "Bob", 8, 30, "a"),
+		commit("b", "Alice", 6, 20, "a"),
+		tag(commit("a", "Bob", 1, 100), "1.0.0", 1),
+	}
+}
+
+func TestReleases(t *testing.T) {
+	report := (&Releases{Semver: true}).Calculate(releaseHistory()).(ReleaseReport)
+	if len(report.Releases) != 2 {
+		t.Fatalf("releases = %+v, want v1.1.0 and 1.0.0", report.Releases)
+	}
+	latest, first := report.Releases[0], report.Releases[1]
+	if latest.Name != "v1.1.0" || latest.Commits != 3 || latest.Lines != 51 || latest.Authors != 2 || latest.DaysSincePrevious != 10 {
+		t.Errorf("v1.1.0 = %+v", latest)
+	}
+	if latest.TopAuthors[0] != (ReleaseAuthor{Author: "Alice", Commits: 2, Lines: 21}) {
+		t.Errorf("v1.1.0 top author = %+v", latest.TopAuthors[0])
+	}
+	if first.Name != "1.0.0" || first.Commits != 1 || first.Date != "2024-03-01" {
+		t.Errorf("1.0.0 = %+v", first)
+	}
+	if report.Unreleased != 1 || report.UnreleasedLines != 5 || report.MeanDaysBetween != 10 {
+		t.Errorf("report = %+v", report)
+	}
+
+	all := (&Releases{}).Calculate(releaseHistory()).(ReleaseReport)
+	if len(all.Releases) != 3 || all.Releases[0].Name != "v1.1.0" || all.Releases[0].Commits != 0 {
+		t.Errorf("all tags = %+v, want nightly then an empty v1.1.0", all.Releases)
+	}
+	if got := (&Releases{Pattern: "v1.*"}).Calculate(releaseHistory()).(ReleaseReport); len(got.Releases) != 1 || got.Releases[0].Commits != 4 {
+		t.Errorf("pattern v1.* = %+v", got.Releases)
+	}
+}
+
+func TestExcludeCommitsKeepsAncestry(t *testing.T) {
+	kept := ExcludeCommits(releaseHistory(), []string{"b", "c"})
+	report := (&Releases{Semver: true}).Calculate(kept).(ReleaseReport)
+	if len(report.Releases) != 2 || report.Releases[0].Commits != 1 || report.Unreleased != 1 {
+		t.Errorf("releases after exclusion = %+v", report)
+	}
+	for _, c := range kept {
+		if c.Hash == "d" && (len(c.Parents) != 1 || c.Parents[0] != "a") {
+			t.Errorf("parents of d = %v, want [a]", c.Parents)
+		}
+	}
+}
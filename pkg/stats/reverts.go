@@ -0,0 +1,142 @@
+package stats
+
+import (
+	"sort"
+	"strings"
+
+	"git-watcher/pkg/analyzer"
+)
+
+// Reverts links revert commits to the commits they revert, by hash or else
+// by subject, and reports how often each author's work is reverted, how long
+// that takes, and how many fixup, squash and small fix commits follow up on
+// earlier work.
+type Reverts struct{}
+
+type RevertLink struct {
+	Revert         string  `json:"revert"`
+	Reverted       string  `json:"reverted"`
+	RevertAuthor   string  `json:"revert_author"`
+	RevertedAuthor string  `json:"reverted_author"`
+	Hours          float64 `json:"hours"`
+}
+
+type AuthorReverts struct {
+	Commits    int     `json:"commits"`
+	Reverted   int     `json:"reverted"`
+	RevertRate float64 `json:"revert_rate"`
+	Reverts    int     `json:"reverts"`
+	Fixups     int     `json:"fixups"`
+	Fixes      int     `json:"fixes"`
+}
+
+type RevertReport struct {
+	Commits    int     `json:"commits"`
+	Reverts    int     `json:"reverts"`
+	Reverted   int     `json:"reverted"`
+	RevertRate float64 `json:"revert_rate"`
+	// Unresolved counts reverts whose target is not in the history.
+	Unresolved            int                       `json:"unresolved"`
+	MeanTimeToRevertHours float64                   `json:"mean_time_to_revert_hours"`
+	Fixups                int                       `json:"fixups"`
+	Fixes                 int                       `json:"fixes"`
+	Authors               map[string]*AuthorReverts `json:"authors"`
+	Links                 []RevertLink              `json:"links"`
+}
+
+func (r *Reverts) Name() string {
+	return "reverts"
+}
+
+func (r *Reverts) Calculate(commits []analyzer.CommitInfo) interface{} {
+	report := RevertReport{Authors: make(map[string]*AuthorReverts), Links: []RevertLink{}}
+
+	bySubject := make(map[string][]int)
+	for i, commit := range commits {
+		subject := strings.TrimSpace(strings.SplitN(commit.Message, "\n", 2)[0])
+		bySubject[subject] = append(bySubject[subject], i)
+		author := report.Authors[commit.Author]
+		if author == nil {
+			author = &AuthorReverts{}
+			report.Authors[commit.Author] = author
+		}
+		author.Commits++
+	}
+	report.Commits = len(commits)
+
+	reverted := make(map[int]bool)
+	var totalHours float64
+	for _, commit := range commits {
+		f := commit.FollowUp
+		if f == nil {
+			continue
+		}
+		author := report.Authors[commit.Author]
+		switch f.Kind {
+		case analyzer.FollowUpFixup, analyzer.FollowUpSquash:
+			author.Fixups++
+			report.Fixups++
+			continue
+		case analyzer.FollowUpFix:
+			author.Fixes++
+			report.Fixes++
+			continue
+		}
+
+		author.Reverts++
+		report.Reverts++
+		target := revertTarget(commits, bySubject, commit)
+		if target < 0 {
+			report.Unresolved++
+			continue
+		}
+		original := commits[target]
+		link := RevertLink{
+			Revert:         commit.Hash,
+			Reverted:       original.Hash,
+			RevertAuthor:   commit.Author,
+			RevertedAuthor: original.Author,
+			Hours:          commit.Date.Sub(original.Date).Hours(),
+		}
+		report.Links = append(report.Links, link)
+		totalHours += link.Hours
+		if !reverted[target] {
+			reverted[target] = true
+			report.Authors[original.Author].Reverted++
+		}
+	}
+
+	report.Reverted = len(reverted)
+	report.RevertRate = ratio(float64(report.Reverted), float64(report.Commits))
+	report.MeanTimeToRevertHours = ratio(totalHours, float64(len(report.Links)))
+	for _, author := range report.Authors {
+		author.RevertRate = ratio(float64(author.Reverted), float64(author.Commits))
+	}
+	sort.Slice(report.Links, func(i, j int) bool { return report.Links[i].Revert < report.Links[j].Revert })
+	return report
+}
+
+// revertTarget finds the index of the commit a revert undoes, preferring the
+// hash from its message and falling back to the latest earlier commit with
+// the reverted subject. It returns -1 when there is none.
+func revertTarget(commits []analyzer.CommitInfo, bySubject map[string][]int, revert analyzer.CommitInfo) int {
+	f := revert.FollowUp
+	if f.Hash != "" {
+		for i, commit := range commits {
+			if strings.HasPrefix(commit.Hash, f.Hash) {
+				return i
+			}
+		}
+	}
+	target := -1
+	for _, i := range bySubject[f.Subject] {
+		candidate := commits[i]
+		if f.Subject == "" || candidate.Hash == revert.Hash || candidate.Date.After(revert.Date) {
+			continue
+		}
+		if target < 0 || candidate.Date.After(commits[target].Date) {
+			target = i
+		}
+	}
+	return target
+}
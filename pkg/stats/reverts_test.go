@@ -0,0 +1,49 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"git-watcher/pkg/analyzer"
+)
+
+func TestReverts(t *testing.T) {
+	at := func(hour int) time.Time { return time.Date(2024, 6, 1, hour, 0, 0, 0, time.UTC) }
+	commit := func(hash, author, message string, hour int) analyzer.CommitInfo {
+		return analyzer.CommitInfo{
+			Hash:     hash,
+			Author:   author,
+			Message:  message,
+			Date:     at(hour),
+			FollowUp: analyzer.ClassifyFollowUp(message),
+		}
+	}
+	commits := []analyzer.CommitInfo{
+		commit("aaaa1111", "Alice", "Add cache", 1),
+		commit("bbbb2222", "Alice", "Tune cache size", 2),
+		commit("cccc3333", "Bob", "Revert \"Add cache\"\n\nThis reverts commit aaaa1111.", 5),
+		commit("dddd4444", "Bob", "Revert \"Tune cache size\"", 12),
+		commit("eeee5555", "Bob", "Revert \"Something from another branch\"", 13),
+		commit("ffff6666", "Alice", "fixup! Tune cache size", 3),
+		commit("gggg7777", "Alice", "Fix typo in docs", 4),
+	}
+
+	report := (&Reverts{}).Calculate(commits).(RevertReport)
+
+	if report.Reverts != 3 || report.Reverted != 2 || report.Unresolved != 1 {
+		t.Errorf("Unexpected revert counts: %+v", report)
+	}
+	if report.MeanTimeToRevertHours != 7 {
+		t.Errorf("Expected a mean time to revert of 7 hours, got %v", report.MeanTimeToRevertHours)
+	}
+	alice := report.Authors["Alice"]
+	if alice.Reverted != 2 || alice.Commits != 4 || alice.RevertRate != 0.5 || alice.Fixups != 1 || alice.Fixes != 1 {
+		t.Errorf("Unexpected revert stats for Alice: %+v", alice)
+	}
+	if bob := report.Authors["Bob"]; bob.Reverts != 3 || bob.Reverted != 0 {
+		t.Errorf("Unexpected revert stats for Bob: %+v", bob)
+	}
+	if len(report.Links) != 2 || report.Links[0].Reverted != "aaaa1111" || report.Links[1].Reverted != "bbbb2222" {
+		t.Errorf("Unexpected revert links: %+v", report.Links)
+	}
+}
@@ -0,0 +1,95 @@
+package stats
+
+import (
+	"sort"
+	"time"
+
+	"git-watcher/pkg/analyzer"
+)
+
+// Signatures counts signed, unsigned and invalid commits per author and for
+// the repository. Signed commits are only checked when the analyzer has a
+// keyring or allowed signers file; otherwise they count as signed.
+type Signatures struct{}
+
+type SignatureCounts struct {
+	Commits    int `json:"commits"`
+	Unsigned   int `json:"unsigned"`
+	Signed     int `json:"signed"`
+	Valid      int `json:"valid"`
+	Invalid    int `json:"invalid"`
+	UnknownKey int `json:"unknown_key"`
+	// SignedRatio is the share of commits carrying any signature.
+	SignedRatio float64 `json:"signed_ratio"`
+}
+
+func (c *SignatureCounts) add(sig analyzer.Signature) {
+	c.Commits++
+	switch sig.Status {
+	case analyzer.SignatureValid:
+		c.Valid++
+	case analyzer.SignatureInvalid:
+		c.Invalid++
+	case analyzer.SignatureUnknownKey:
+		c.UnknownKey++
+	case analyzer.SignatureSigned:
+		c.Signed++
+	default:
+		c.Unsigned++
+	}
+}
+
+type SignatureReport struct {
+	SignatureCounts
+	Types   map[string]int              `json:"types"`
+	Authors map[string]*SignatureCounts `json:"authors"`
+}
+
+func (s *Signatures) Name() string {
+	return "signatures"
+}
+
+func (s *Signatures) Calculate(commits []analyzer.CommitInfo) interface{} {
+	report := SignatureReport{Types: make(map[string]int), Authors: make(map[string]*SignatureCounts)}
+	for _, commit := range commits {
+		author := report.Authors[commit.Author]
+		if author == nil {
+			author = &SignatureCounts{}
+			report.Authors[commit.Author] = author
+		}
+		author.add(commit.Signature)
+		report.add(commit.Signature)
+		if commit.Signature.Type != "" {
+			report.Types[commit.Signature.Type]++
+		}
+	}
+
+	report.SignedRatio = ratio(float64(report.Commits-report.Unsigned), float64(report.Commits))
+	for _, author := range report.Authors {
+		author.SignedRatio = ratio(float64(author.Commits-author.Unsigned), float64(author.Commits))
+	}
+	return report
+}
+
+// UnsignedSince returns the commits made at or after since that are unsigned
+// or carry an invalid signature, oldest first. When verified, signatures were
+// checked against trusted keys and anything but a valid signature fails,
+// including signatures by unknown keys.
+func UnsignedSince(commits []analyzer.CommitInfo, since time.Time, verified bool) []analyzer.CommitInfo {
+	var unsigned []analyzer.CommitInfo
+	for _, commit := range commits {
+		if commit.Date.Before(since) {
+			continue
+		}
+		status := commit.Signature.Status
+		failed := status == analyzer.SignatureUnsigned || status == analyzer.SignatureInvalid || status == ""
+		if verified {
+			failed = status != analyzer.SignatureValid
+		}
+		if failed {
+			unsigned = append(unsigned, commit)
+		}
+	}
+	sort.Slice(unsigned, func(i, j int) bool { return unsigned[i].Date.Before(unsigned[j].Date) })
+	return unsigned
+}
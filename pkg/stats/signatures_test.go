@@ -0,0 +1,46 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"git-watcher/pkg/analyzer"
+)
+
+func TestSignatures(t *testing.T) {
+	commit := func(hash, author string, day int, sig analyzer.Signature) analyzer.CommitInfo {
+		return analyzer.CommitInfo{Hash: hash, Author: author, Date: time.Date(2024, 6, day, 12, 0, 0, 0, time.UTC), Signature: sig}
+	}
+	unsigned := analyzer.Signature{Status: analyzer.SignatureUnsigned}
+	commits := []analyzer.CommitInfo{
+		commit("a1", "Alice", 1, analyzer.Signature{Type: "ssh", Status: analyzer.SignatureValid}),
+		commit("a2", "Alice", 2, analyzer.Signature{Type: "gpg", Status: analyzer.SignatureInvalid}),
+		commit("a3", "Alice", 3, unsigned),
+		commit("b1", "Bob", 1, unsigned),
+		commit("b2", "Bob", 4, analyzer.Signature{Type: "gpg", Status: analyzer.SignatureUnknownKey}),
+		commit("b3", "Bob", 5, analyzer.Signature{Type: "x509", Status: analyzer.SignatureSigned}),
+	}
+
+	report := (&Signatures{}).Calculate(commits).(SignatureReport)
+	want := SignatureCounts{Commits: 6, Unsigned: 2, Signed: 1, Valid: 1, Invalid: 1, UnknownKey: 1, SignedRatio: 4.0 / 6}
+	if report.SignatureCounts != want {
+		t.Errorf("repository counts = %+v, want %+v", report.SignatureCounts, want)
+	}
+	if report.Types["gpg"] != 2 || report.Types["ssh"] != 1 || report.Types["x509"] != 1 {
+		t.Errorf("types = %v", report.Types)
+	}
+	if alice := report.Authors["Alice"]; alice.Invalid != 1 || alice.Unsigned != 1 || alice.SignedRatio != 2.0/3 {
+		t.Errorf("Alice = %+v", alice)
+	}
+
+	since := time.Date(2024, 6, 2, 0, 0, 0, 0, time.UTC)
+	late := UnsignedSince(commits, since, false)
+	if len(late) != 2 || late[0].Hash != "a2" || late[1].Hash != "a3" {
+		t.Errorf("UnsignedSince = %v", late)
+	}
+	// with trusted keys, unknown keys and unverifiable signatures fail too
+	late = UnsignedSince(commits, since, true)
+	if len(late) != 4 || late[2].Hash != "b2" || late[3].Hash != "b3" {
+		t.Errorf("UnsignedSince(verified) = %v", late)
+	}
+}
@@ -0,0 +1,187 @@
+package stats
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"git-watcher/pkg/analyzer"
+)
+
+// CommitSizeBuckets are the lower bounds, in changed lines, of the commit
+// size histogram; the last bucket is open ended.
+var CommitSizeBuckets = []int64{0, 10, 50, 100, 500, 1000, 5000}
+
+// CommitSizes reports the distribution of changed lines per commit for the
+// repository and each author, and flags outliers: commits beyond the far-out
+// fence Q3 + 3×IQR of the repository, and never smaller than OutlierLines.
+// CommitURL links outliers to a web view, {hash} is replaced by the hash.
+type CommitSizes struct {
+	OutlierLines int64
+	CommitURL    string
+	Top          int
+}
+
+type SizeBucket struct {
+	Label   string `json:"label"`
+	Min     int64  `json:"min"`
+	Commits int    `json:"commits"`
+}
+
+type SizeDistribution struct {
+	Commits   int          `json:"commits"`
+	Median    int64        `json:"median"`
+	P90       int64        `json:"p90"`
+	P99       int64        `json:"p99"`
+	Max       int64        `json:"max"`
+	Histogram []SizeBucket `json:"histogram"`
+}
+
+type SizeOutlier struct {
+	Hash    string `json:"hash"`
+	URL     string `json:"url,omitempty"`
+	Author  string `json:"author"`
+	Date    string `json:"date"`
+	Subject string `json:"subject"`
+	Lines   int64  `json:"lines"`
+	Files   int    `json:"files"`
+	// Kind guesses what the commit is: reformat, bulk_add, bulk_delete or
+	// large.
+	Kind string `json:"kind"`
+}
+
+type CommitSizeReport struct {
+	Repository       SizeDistribution            `json:"repository"`
+	Authors          map[string]SizeDistribution `json:"authors"`
+	OutlierThreshold int64                       `json:"outlier_threshold"`
+	Outliers         []SizeOutlier               `json:"outliers"`
+}
+
+func (c *CommitSizes) Name() string {
+	return "commit_sizes"
+}
+
+func (c *CommitSizes) Calculate(commits []analyzer.CommitInfo) interface{} {
+	minLines := c.OutlierLines
+	if minLines <= 0 {
+		minLines = 1000
+	}
+
+	all := make([]int64, 0, len(commits))
+	byAuthor := make(map[string][]int64)
+	for _, commit := range commits {
+		all = append(all, commit.LineCount)
+		byAuthor[commit.Author] = append(byAuthor[commit.Author], commit.LineCount)
+	}
+
+	report := CommitSizeReport{
+		Repository: sizeDistribution(all),
+		Authors:    make(map[string]SizeDistribution, len(byAuthor)),
+		Outliers:   []SizeOutlier{},
+	}
+	for author, sizes := range byAuthor {
+		report.Authors[author] = sizeDistribution(sizes)
+	}
+
+	q1, q3 := percentile(all, 0.25), percentile(all, 0.75)
+	report.OutlierThreshold = q3 + 3*(q3-q1)
+	if report.OutlierThreshold < minLines {
+		report.OutlierThreshold = minLines
+	}
+	for _, commit := range commits {
+		if commit.LineCount <= report.OutlierThreshold {
+			continue
+		}
+		outlier := SizeOutlier{
+			Hash:    commit.Hash,
+			Author:  commit.Author,
+			Date:    commit.Date.Format("2006-01-02 15:04:05"),
+			Subject: strings.SplitN(strings.TrimSpace(commit.Message), "\n", 2)[0],
+			Lines:   commit.LineCount,
+			Files:   len(commit.Files),
+			Kind:    sizeKind(commit),
+		}
+		if c.CommitURL != "" {
+			outlier.URL = strings.ReplaceAll(c.CommitURL, "{hash}", commit.Hash)
+		}
+		report.Outliers = append(report.Outliers, outlier)
+	}
+	sort.Slice(report.Outliers, func(i, j int) bool {
+		return report.Outliers[i].Lines > report.Outliers[j].Lines
+	})
+	if c.Top > 0 && len(report.Outliers) > c.Top {
+		report.Outliers = report.Outliers[:c.Top]
+	}
+	return report
+}
+
+func sizeDistribution(sizes []int64) SizeDistribution {
+	sorted := append([]int64(nil), sizes...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	dist := SizeDistribution{
+		Commits:   len(sorted),
+		Median:    percentile(sorted, 0.5),
+		P90:       percentile(sorted, 0.9),
+		P99:       percentile(sorted, 0.99),
+		Histogram: make([]SizeBucket, len(CommitSizeBuckets)),
+	}
+	if len(sorted) > 0 {
+		dist.Max = sorted[len(sorted)-1]
+	}
+	for i, min := range CommitSizeBuckets {
+		label := strconv.FormatInt(min, 10) + "+"
+		if i+1 < len(CommitSizeBuckets) {
+			label = strconv.FormatInt(min, 10) + "-" + strconv.FormatInt(CommitSizeBuckets[i+1]-1, 10)
+		}
+		dist.Histogram[i] = SizeBucket{Label: label, Min: min}
+	}
+	for _, size := range sorted {
+		i := sort.Search(len(CommitSizeBuckets), func(i int) bool { return CommitSizeBuckets[i] > size }) - 1
+		if i < 0 {
+			i = 0
+		}
+		dist.Histogram[i].Commits++
+	}
+	return dist
+}
+
+// percentile returns the nearest-rank p-th percentile of sizes, sorting a
+// copy when needed.
+func percentile(sizes []int64, p float64) int64 {
+	if len(sizes) == 0 {
+		return 0
+	}
+	sorted := sizes
+	if !sort.SliceIsSorted(sizes, func(i, j int) bool { return sizes[i] < sizes[j] }) {
+		sorted = append([]int64(nil), sizes...)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	}
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
+
+func sizeKind(commit analyzer.CommitInfo) string {
+	var added, deleted int
+	for _, f := range commit.Files {
+		added += f.Additions
+		deleted += f.Deletions
+	}
+	total := added + deleted
+	switch {
+	case total == 0:
+		return "large"
+	case len(commit.Files) >= 10 && math.Min(float64(added), float64(deleted)) >= 0.8*math.Max(float64(added), float64(deleted)):
+		return "reformat"
+	case float64(deleted) <= 0.05*float64(total):
+		return "bulk_add"
+	case float64(added) <= 0.05*float64(total):
+		return "bulk_delete"
+	default:
+		return "large"
+	}
+}
@@ -0,0 +1,69 @@
+package stats
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"git-watcher/pkg/analyzer"
+)
+
+func TestCommitSizes(t *testing.T) {
+	var commits []analyzer.CommitInfo
+	for i := 1; i <= 20; i++ {
+		commits = append(commits, analyzer.CommitInfo{
+			Hash:      fmt.Sprintf("%040d", i),
+			Author:    "Alice",
+			Date:      time.Date(2024, 1, i, 10, 0, 0, 0, time.UTC),
+			LineCount: int64(i * 5),
+			Files:     []analyzer.FileChange{{Path: "a.go", Additions: i * 4, Deletions: i}},
+		})
+	}
+	reformat := make([]analyzer.FileChange, 12)
+	for i := range reformat {
+		reformat[i] = analyzer.FileChange{Path: fmt.Sprintf("pkg/%d.go", i), Additions: 200, Deletions: 190}
+	}
+	commits = append(commits,
+		analyzer.CommitInfo{Hash: "big", Author: "Bob", Message: "Reformat everything\n\nran gofmt", LineCount: 4680, Files: reformat},
+		analyzer.CommitInfo{Hash: "vendor", Author: "Bob", LineCount: 20000, Files: []analyzer.FileChange{{Path: "vendor/x.go", Additions: 20000}}},
+	)
+
+	stat := &CommitSizes{CommitURL: "https://example.com/commit/{hash}"}
+	report := stat.Calculate(commits).(CommitSizeReport)
+
+	repo := report.Repository
+	if repo.Commits != 22 || repo.Median != 55 || repo.P90 != 100 || repo.P99 != 20000 || repo.Max != 20000 {
+		t.Errorf("Unexpected repository distribution: %+v", repo)
+	}
+	if repo.Histogram[0].Label != "0-9" || repo.Histogram[0].Commits != 1 || repo.Histogram[2].Commits != 10 {
+		t.Errorf("Unexpected histogram: %+v", repo.Histogram)
+	}
+	last := repo.Histogram[len(repo.Histogram)-1]
+	if last.Label != "5000+" || last.Commits != 1 {
+		t.Errorf("Unexpected open bucket: %+v", last)
+	}
+	if alice := report.Authors["Alice"]; alice.Commits != 20 || alice.Max != 100 {
+		t.Errorf("Unexpected distribution for Alice: %+v", alice)
+	}
+
+	if len(report.Outliers) != 2 {
+		t.Fatalf("Expected 2 outliers over %d lines, got %+v", report.OutlierThreshold, report.Outliers)
+	}
+	if o := report.Outliers[0]; o.Hash != "vendor" || o.Kind != "bulk_add" || o.URL != "https://example.com/commit/vendor" {
+		t.Errorf("Unexpected first outlier: %+v", o)
+	}
+	if o := report.Outliers[1]; o.Kind != "reformat" || o.Subject != "Reformat everything" || o.Files != 12 {
+		t.Errorf("Unexpected second outlier: %+v", o)
+	}
+}
+
+func TestExcludeCommits(t *testing.T) {
+	commits := []analyzer.CommitInfo{{Hash: "abc123"}, {Hash: "def456"}, {Hash: "abd789"}}
+	kept := ExcludeCommits(commits, []string{"ABC", "def456"})
+	if len(kept) != 1 || kept[0].Hash != "abd789" {
+		t.Errorf("Unexpected commits after exclusion: %+v", kept)
+	}
+	if got := ExcludeCommits(commits, nil); len(got) != 3 {
+		t.Errorf("Expected nothing excluded without prefixes, got %d commits", len(got))
+	}
+}
@@ -2,6 +2,8 @@ package stats
 
 import (
 	"git-watcher/pkg/analyzer"
+	"git-watcher/pkg/policy"
+	"strings"
 	"time"
 )
 
@@ -51,7 +53,9 @@ func (l *LatestCommit) Calculate(commits []analyzer.CommitInfo) interface{} {
 	return latest
 }
 
-type LateNightCommits struct{}
+type LateNightCommits struct {
+	Policies *policy.Set
+}
 
 func (l *LateNightCommits) Name() string {
 	return "late_night_commits"
@@ -62,8 +66,7 @@ func (l *LateNightCommits) Calculate(commits []analyzer.CommitInfo) interface{}
 	lateNightAuthors := make(map[string]int)
 
 	for _, commit := range commits {
-		hour := commit.Date.Hour()
-		if hour >= 23 || hour <= 6 {
+		if l.Policies.For(commit.Author, commit.Email).IsLateNight(commit.Date) {
 			lateNightCount++
 			lateNightAuthors[commit.Author]++
 		}
@@ -92,17 +95,106 @@ func (c *CommitActivityByHour) Calculate(commits []analyzer.CommitInfo) interfac
 
 type StatsCalculator struct {
 	statistics []Statistics
+	timeZone   *TimeZone
+}
+
+// Options tunes the statistics registered by NewStatsCalculatorWithOptions.
+type Options struct {
+	// Top limits ranked statistics to their first N entries, 0 keeps all.
+	Top int
+	// HotspotWindow restricts hotspot analysis to commits made within this
+	// duration of the latest commit, 0 uses the whole history.
+	HotspotWindow time.Duration
+	// CouplingMinSupport is the number of shared commits needed before two
+	// files are reported as coupled.
+	CouplingMinSupport int
+	// CouplingMaxFiles skips commits touching more files than this when
+	// looking for coupled files.
+	CouplingMaxFiles int
+	// HeatmapByAuthor adds a weekday/hour matrix per author to the heatmap.
+	HeatmapByAuthor bool
+	// TrendBucket is the bucket size of the trend series.
+	TrendBucket TrendBucket
+	// TimeZone converts commit dates before time-bucketed statistics see
+	// them, nil keeps the offsets commits were recorded with.
+	TimeZone *TimeZone
+	// Policies decide working days, hours and the late-night window per
+	// author, nil applies policy.Default to everyone.
+	Policies *policy.Set
+	// Wellbeing holds the thresholds that flag authors at risk of burnout.
+	Wellbeing WellbeingThresholds
+	// CohortHorizons are the months after their first commit at which
+	// newcomers are checked for retention.
+	CohortHorizons []int
+	// InactiveAfter is how long without a commit before an author counts
+	// as inactive.
+	InactiveAfter time.Duration
+	// OutlierLines is the smallest commit, in changed lines, flagged as a
+	// size outlier.
+	OutlierLines int64
+	// CommitURL links commits to a web view, {hash} is replaced by the
+	// commit hash.
+	CommitURL string
+	// ExcludeCommits are hash prefixes of commits, such as vendoring or
+	// reformatting commits, that callers drop with ExcludeCommits before
+	// computing any statistic.
+	ExcludeCommits []string
+	// CommitTypes are the Conventional Commits types a repository allows,
+	// empty accepts any type.
+	CommitTypes []string
+	// MessageRules score commit messages, nil uses DefaultMessageRules.
+	MessageRules *MessageRules
+	// ReleasePattern is a glob selecting the tags that count as releases,
+	// empty for all tags; ReleaseSemver also requires a semantic version.
+	ReleasePattern string
+	ReleaseSemver  bool
+}
+
+func DefaultOptions() Options {
+	return Options{
+		Top:                20,
+		CouplingMinSupport: 2,
+		CouplingMaxFiles:   30,
+		TrendBucket:        BucketMonth,
+		Wellbeing:          DefaultWellbeingThresholds(),
+		CohortHorizons:     []int{3, 6, 12},
+		InactiveAfter:      90 * 24 * time.Hour,
+		OutlierLines:       1000,
+	}
 }
 
 func NewStatsCalculator() *StatsCalculator {
+	return NewStatsCalculatorWithOptions(DefaultOptions())
+}
+
+func NewStatsCalculatorWithOptions(opts Options) *StatsCalculator {
 	return &StatsCalculator{
+		timeZone: opts.TimeZone,
 		statistics: []Statistics{
 			&CommitCountByAuthor{},
 			&LatestCommit{},
-			&LateNightCommits{},
+			&LateNightCommits{Policies: opts.Policies},
 			&CommitActivityByHour{},
-			&WeekendCommits{},
+			&WeekendCommits{Policies: opts.Policies},
+			&HolidayCommits{Policies: opts.Policies},
 			&CommitLineCountByAuthor{},
+			&CommitSizes{OutlierLines: opts.OutlierLines, CommitURL: opts.CommitURL, Top: opts.Top},
+			&FileHotspots{Top: opts.Top, Window: opts.HotspotWindow},
+			&TemporalCoupling{Top: opts.Top, MinSupport: opts.CouplingMinSupport, MaxFiles: opts.CouplingMaxFiles},
+			&WeekdayHourHeatmap{ByAuthor: opts.HeatmapByAuthor},
+			&ContributionCalendar{},
+			&Trend{Bucket: opts.TrendBucket, Policies: opts.Policies},
+			&Overtime{Policies: opts.Policies},
+			&Streaks{},
+			&ConventionalCommits{Types: opts.CommitTypes, Bucket: opts.TrendBucket},
+			&TicketReferences{Top: opts.Top},
+			&Reverts{},
+			&MessageQuality{Rules: opts.MessageRules, Top: opts.Top},
+			&Signatures{},
+			&Releases{Pattern: opts.ReleasePattern, Semver: opts.ReleaseSemver, Top: opts.Top},
+			&Cohorts{Horizons: opts.CohortHorizons, InactiveAfter: opts.InactiveAfter},
+			&Wellbeing{Policies: opts.Policies, Thresholds: opts.Wellbeing},
+			&UTCOffsetsByAuthor{},
 			/*
 				you just need to implement Statistics interface
 				and add it here
@@ -117,16 +209,77 @@ func (sc *StatsCalculator) AddStatistic(stat Statistics) {
 
 func (sc *StatsCalculator) CalculateAll(commits []analyzer.CommitInfo) map[string]interface{} {
 	results := make(map[string]interface{})
+	local := sc.timeZone.Localize(commits)
 
 	for _, stat := range sc.statistics {
-		results[stat.Name()] = stat.Calculate(commits)
+		if _, ok := stat.(recordedTimeStatistic); ok {
+			results[stat.Name()] = stat.Calculate(commits)
+			continue
+		}
+		results[stat.Name()] = stat.Calculate(local)
 	}
 
 	return results
 }
 
+// ExcludeCommits drops commits whose hash starts with any of the prefixes,
+// ignoring case. Kept commits inherit the parents of excluded ones so that
+// ancestry is preserved.
+func ExcludeCommits(commits []analyzer.CommitInfo, prefixes []string) []analyzer.CommitInfo {
+	if len(prefixes) == 0 {
+		return commits
+	}
+	kept := make([]analyzer.CommitInfo, 0, len(commits))
+	excluded := make(map[string][]string)
+	for _, commit := range commits {
+		skip := false
+		for _, prefix := range prefixes {
+			if prefix != "" && strings.HasPrefix(commit.Hash, strings.ToLower(prefix)) {
+				skip = true
+				break
+			}
+		}
+		if skip {
+			excluded[commit.Hash] = commit.Parents
+		} else {
+			kept = append(kept, commit)
+		}
+	}
+	if len(excluded) > 0 {
+		for i := range kept {
+			kept[i].Parents = bypassExcluded(kept[i].Parents, excluded)
+		}
+	}
+	return kept
+}
+
+// bypassExcluded replaces excluded parents by their own parents.
+func bypassExcluded(parents []string, excluded map[string][]string) []string {
+	var result []string
+	seen := make(map[string]bool)
+	var visit func(hash string)
+	visit = func(hash string) {
+		if seen[hash] {
+			return
+		}
+		seen[hash] = true
+		if grandparents, ok := excluded[hash]; ok {
+			for _, p := range grandparents {
+				visit(p)
+			}
+			return
+		}
+		result = append(result, hash)
+	}
+	for _, p := range parents {
+		visit(p)
+	}
+	return result
+}
+
 type WeekendCommits struct {
 	//this is fucking truly work life balance
+	Policies *policy.Set
 }
 
 func (w *WeekendCommits) Name() string {
@@ -137,12 +290,8 @@ func (w *WeekendCommits) Calculate(commits []analyzer.CommitInfo) interface{} {
 	weekendCount := 0
 	weekendAuthors := make(map[string]int)
 
-	isWeekend := func(date time.Time) bool {
-		weekday := date.Weekday()
-		return weekday == time.Saturday || weekday == time.Sunday
-	}
 	for _, commit := range commits {
-		if isWeekend(commit.Date) {
+		if w.Policies.For(commit.Author, commit.Email).IsWeekend(commit.Date) {
 			weekendCount++
 			weekendAuthors[commit.Author]++
 		}
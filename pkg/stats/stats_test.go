@@ -1,10 +1,13 @@
 package stats
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"git-watcher/pkg/analyzer"
+	"git-watcher/pkg/policy"
 )
 
 func TestCommitCountByAuthor(t *testing.T) {
@@ -109,4 +112,50 @@ func TestCommitLineCountByAuthor(t *testing.T) {
 	if result["Bob"] != 50 {
 		t.Errorf("Expected Bob to have 50 lines, got %d", result["Bob"])
 	}
-}
\ No newline at end of file
+}
+
+func TestLateNightCommitsEndsAtSix(t *testing.T) {
+	commits := []analyzer.CommitInfo{
+		{Author: "Alice", Date: time.Date(2024, 1, 1, 5, 59, 0, 0, time.UTC)}, // 05:59 - late night
+		{Author: "Bob", Date: time.Date(2024, 1, 1, 6, 30, 0, 0, time.UTC)},   // 06:30 - morning
+	}
+
+	stat := &LateNightCommits{}
+	result := stat.Calculate(commits).(map[string]interface{})
+
+	if total := result["total"].(int); total != 1 {
+		t.Errorf("Expected 1 late night commit, got %d", total)
+	}
+	if _, ok := result["authors"].(map[string]int)["Bob"]; ok {
+		t.Errorf("Expected Bob's 06:30 commit not to count as late night")
+	}
+}
+
+func TestWeekendCommitsFollowPolicy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	config := "authors:\n  Alice:\n    work_days: [sun, mon, tue, wed, thu]\n"
+	if err := os.WriteFile(path, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	policies, err := policy.Load(path)
+	if err != nil {
+		t.Fatalf("Failed to load policy: %v", err)
+	}
+
+	commits := []analyzer.CommitInfo{
+		{Author: "Alice", Date: time.Date(2024, 7, 28, 10, 0, 0, 0, time.UTC)}, // Sunday, a work day for Alice
+		{Author: "Alice", Date: time.Date(2024, 7, 26, 10, 0, 0, 0, time.UTC)}, // Friday, Alice's weekend
+		{Author: "Bob", Date: time.Date(2024, 7, 28, 10, 0, 0, 0, time.UTC)},   // Sunday
+	}
+
+	stat := &WeekendCommits{Policies: policies}
+	result := stat.Calculate(commits).(map[string]interface{})
+
+	if total := result["total"].(int); total != 2 {
+		t.Errorf("Expected 2 weekend commits, got %d", total)
+	}
+	authors := result["authors"].(map[string]int)
+	if authors["Alice"] != 1 || authors["Bob"] != 1 {
+		t.Errorf("Unexpected weekend authors: %v", authors)
+	}
+}
@@ -0,0 +1,126 @@
+package stats
+
+import (
+	"sort"
+	"time"
+
+	"git-watcher/pkg/analyzer"
+)
+
+// Streaks reports per author how many days they committed on, their longest
+// and current runs of consecutive commit days, and how long they have been
+// around. Days follow the commit's local date, so run it after the commits
+// have been moved to the configured timezone. A streak is current when it
+// ends on the day of AsOf or the day before; a zero AsOf means now.
+type Streaks struct {
+	AsOf time.Time
+}
+
+type AuthorStreaks struct {
+	ActiveDays          int     `json:"active_days"`
+	LongestStreak       int     `json:"longest_streak"`
+	CurrentStreak       int     `json:"current_streak"`
+	CommitsPerActiveDay float64 `json:"commits_per_active_day"`
+	FirstCommit         string  `json:"first_commit"`
+	LastCommit          string  `json:"last_commit"`
+	TenureDays          int     `json:"tenure_days"`
+}
+
+func (s *Streaks) Name() string {
+	return "streaks"
+}
+
+func (s *Streaks) Calculate(commits []analyzer.CommitInfo) interface{} {
+	asOf := s.AsOf
+	if asOf.IsZero() {
+		asOf = time.Now()
+	}
+
+	times := make(map[string][]time.Time)
+	for _, commit := range commits {
+		times[commit.Author] = append(times[commit.Author], commit.Date)
+	}
+
+	result := make(map[string]AuthorStreaks, len(times))
+	for author, dates := range times {
+		days := commitDays(dates)
+		first, last := days[0], days[len(days)-1]
+		streaks := AuthorStreaks{
+			ActiveDays:          len(days),
+			LongestStreak:       longestStreak(days),
+			CommitsPerActiveDay: float64(len(dates)) / float64(len(days)),
+			FirstCommit:         first.Format(calendarDateFormat),
+			LastCommit:          last.Format(calendarDateFormat),
+			TenureDays:          daysBetween(first, last) + 1,
+		}
+
+		// compare against the date of AsOf where the author works
+		local := asOf.In(latestOf(dates).Location())
+		today := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+		if gap := daysBetween(last, today); gap == 0 || gap == 1 {
+			streaks.CurrentStreak = trailingStreak(days)
+		}
+		result[author] = streaks
+	}
+	return result
+}
+
+// commitDays returns the distinct calendar days of the given times, oldest
+// first. Days follow each time's own location.
+func commitDays(times []time.Time) []time.Time {
+	seen := make(map[time.Time]bool)
+	days := make([]time.Time, 0, len(times))
+	for _, t := range times {
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		if !seen[day] {
+			seen[day] = true
+			days = append(days, day)
+		}
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+	return days
+}
+
+// longestStreak returns the longest run of consecutive days in sorted days.
+func longestStreak(days []time.Time) int {
+	longest, run := 0, 0
+	for i, day := range days {
+		if i > 0 && days[i-1].AddDate(0, 0, 1).Equal(day) {
+			run++
+		} else {
+			run = 1
+		}
+		if run > longest {
+			longest = run
+		}
+	}
+	return longest
+}
+
+// trailingStreak returns the run of consecutive days ending with the last of
+// sorted days.
+func trailingStreak(days []time.Time) int {
+	run := 0
+	for i := len(days) - 1; i >= 0; i-- {
+		if i < len(days)-1 && !days[i].AddDate(0, 0, 1).Equal(days[i+1]) {
+			break
+		}
+		run++
+	}
+	return run
+}
+
+// daysBetween counts calendar days from a to b, both UTC midnights.
+func daysBetween(a, b time.Time) int {
+	return int(b.Sub(a).Hours() / 24)
+}
+
+func latestOf(times []time.Time) time.Time {
+	latest := times[0]
+	for _, t := range times {
+		if t.After(latest) {
+			latest = t
+		}
+	}
+	return latest
+}
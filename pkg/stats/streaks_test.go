@@ -0,0 +1,50 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"git-watcher/pkg/analyzer"
+)
+
+func TestStreaks(t *testing.T) {
+	shanghai := time.FixedZone("UTC+8", 8*3600)
+	commits := []analyzer.CommitInfo{
+		{Author: "Alice", Date: time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)},
+		{Author: "Alice", Date: time.Date(2024, 3, 2, 10, 0, 0, 0, time.UTC)},
+		{Author: "Alice", Date: time.Date(2024, 3, 2, 15, 0, 0, 0, time.UTC)},
+		{Author: "Alice", Date: time.Date(2024, 3, 3, 10, 0, 0, 0, time.UTC)},
+		{Author: "Alice", Date: time.Date(2024, 3, 9, 10, 0, 0, 0, time.UTC)},
+		{Author: "Alice", Date: time.Date(2024, 3, 10, 10, 0, 0, 0, time.UTC)},
+		// 00:30 in Shanghai is still March 10th in UTC
+		{Author: "Bob", Date: time.Date(2024, 3, 11, 0, 30, 0, 0, shanghai)},
+		{Author: "Bob", Date: time.Date(2024, 3, 9, 23, 0, 0, 0, shanghai)},
+	}
+
+	stat := &Streaks{AsOf: time.Date(2024, 3, 11, 9, 0, 0, 0, time.UTC)}
+	result := stat.Calculate(commits).(map[string]AuthorStreaks)
+
+	alice := result["Alice"]
+	want := AuthorStreaks{
+		ActiveDays:          5,
+		LongestStreak:       3,
+		CurrentStreak:       2,
+		CommitsPerActiveDay: 1.2,
+		FirstCommit:         "2024-03-01",
+		LastCommit:          "2024-03-10",
+		TenureDays:          10,
+	}
+	if alice != want {
+		t.Errorf("Unexpected streaks for Alice:\n got  %+v\n want %+v", alice, want)
+	}
+
+	bob := result["Bob"]
+	if bob.ActiveDays != 2 || bob.LongestStreak != 1 || bob.LastCommit != "2024-03-11" || bob.CurrentStreak != 1 {
+		t.Errorf("Expected Bob's days to follow his own timezone, got %+v", bob)
+	}
+
+	stat.AsOf = time.Date(2024, 3, 20, 9, 0, 0, 0, time.UTC)
+	if got := stat.Calculate(commits).(map[string]AuthorStreaks)["Alice"].CurrentStreak; got != 0 {
+		t.Errorf("Expected no current streak after a gap, got %d", got)
+	}
+}
@@ -0,0 +1,120 @@
+package stats
+
+import (
+	"sort"
+	"strings"
+
+	"git-watcher/pkg/analyzer"
+)
+
+// TicketReferences measures how many commits reference an issue tracker
+// ticket, overall and per author, and ranks the most referenced tickets.
+type TicketReferences struct {
+	Top int
+}
+
+type TicketCoverage struct {
+	Commits    int     `json:"commits"`
+	WithTicket int     `json:"with_ticket"`
+	Ratio      float64 `json:"ratio"`
+}
+
+type TicketCount struct {
+	Ticket      string `json:"ticket"`
+	Commits     int    `json:"commits"`
+	Authors     int    `json:"authors"`
+	FirstCommit string `json:"first_commit"`
+	LastCommit  string `json:"last_commit"`
+}
+
+type TicketReport struct {
+	TicketCoverage
+	Tickets int                        `json:"tickets"`
+	Authors map[string]*TicketCoverage `json:"authors"`
+	Top     []TicketCount              `json:"top"`
+}
+
+func (t *TicketReferences) Name() string {
+	return "ticket_references"
+}
+
+func (t *TicketReferences) Calculate(commits []analyzer.CommitInfo) interface{} {
+	report := TicketReport{Authors: make(map[string]*TicketCoverage), Top: []TicketCount{}}
+
+	type acc struct {
+		count       TicketCount
+		authors     map[string]bool
+		first, last analyzer.CommitInfo
+	}
+	tickets := make(map[string]*acc)
+	for _, commit := range commits {
+		author := report.Authors[commit.Author]
+		if author == nil {
+			author = &TicketCoverage{}
+			report.Authors[commit.Author] = author
+		}
+		author.Commits++
+		report.Commits++
+		if len(commit.Tickets) == 0 {
+			continue
+		}
+		author.WithTicket++
+		report.WithTicket++
+
+		for _, ticket := range commit.Tickets {
+			a := tickets[ticket]
+			if a == nil {
+				a = &acc{count: TicketCount{Ticket: ticket}, authors: make(map[string]bool), first: commit, last: commit}
+				tickets[ticket] = a
+			}
+			a.count.Commits++
+			a.authors[commit.Author] = true
+			if commit.Date.Before(a.first.Date) {
+				a.first = commit
+			}
+			if commit.Date.After(a.last.Date) {
+				a.last = commit
+			}
+		}
+	}
+
+	for _, author := range report.Authors {
+		author.Ratio = ratio(float64(author.WithTicket), float64(author.Commits))
+	}
+	report.Ratio = ratio(float64(report.WithTicket), float64(report.Commits))
+	report.Tickets = len(tickets)
+
+	for _, a := range tickets {
+		a.count.Authors = len(a.authors)
+		a.count.FirstCommit = a.first.Date.Format(calendarDateFormat)
+		a.count.LastCommit = a.last.Date.Format(calendarDateFormat)
+		report.Top = append(report.Top, a.count)
+	}
+	sort.Slice(report.Top, func(i, j int) bool {
+		if report.Top[i].Commits != report.Top[j].Commits {
+			return report.Top[i].Commits > report.Top[j].Commits
+		}
+		return report.Top[i].Ticket < report.Top[j].Ticket
+	})
+	if t.Top > 0 && len(report.Top) > t.Top {
+		report.Top = report.Top[:t.Top]
+	}
+	return report
+}
+
+// CommitsForTicket returns the commits that reference ticket, ignoring case,
+// newest first.
+func CommitsForTicket(commits []analyzer.CommitInfo, ticket string) []analyzer.CommitInfo {
+	ticket = strings.TrimSpace(ticket)
+	var matched []analyzer.CommitInfo
+	for _, commit := range commits {
+		for _, t := range commit.Tickets {
+			if strings.EqualFold(t, ticket) {
+				matched = append(matched, commit)
+				break
+			}
+		}
+	}
+	sort.Slice(matched, func(i, j int) bool { return matched[i].Date.After(matched[j].Date) })
+	return matched
+}
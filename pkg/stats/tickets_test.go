@@ -0,0 +1,41 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"git-watcher/pkg/analyzer"
+)
+
+func TestTicketReferences(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 5, d, 12, 0, 0, 0, time.UTC) }
+	commits := []analyzer.CommitInfo{
+		{Hash: "a1", Author: "Alice", Date: day(1), Tickets: []string{"PAY-1"}},
+		{Hash: "a2", Author: "Alice", Date: day(3), Tickets: []string{"PAY-1", "#12"}},
+		{Hash: "a3", Author: "Alice", Date: day(4)},
+		{Hash: "b1", Author: "Bob", Date: day(2), Tickets: []string{"PAY-1"}},
+		{Hash: "b2", Author: "Bob", Date: day(5)},
+	}
+
+	stat := &TicketReferences{Top: 1}
+	report := stat.Calculate(commits).(TicketReport)
+
+	if report.Commits != 5 || report.WithTicket != 3 || report.Ratio != 0.6 || report.Tickets != 2 {
+		t.Errorf("Unexpected totals: %+v", report)
+	}
+	if alice := report.Authors["Alice"]; alice.WithTicket != 2 || alice.Commits != 3 {
+		t.Errorf("Unexpected coverage for Alice: %+v", alice)
+	}
+	want := TicketCount{Ticket: "PAY-1", Commits: 3, Authors: 2, FirstCommit: "2024-05-01", LastCommit: "2024-05-03"}
+	if len(report.Top) != 1 || report.Top[0] != want {
+		t.Errorf("Unexpected top tickets: %+v", report.Top)
+	}
+
+	matched := CommitsForTicket(commits, "PAY-1")
+	if len(matched) != 3 || matched[0].Hash != "a2" || matched[2].Hash != "a1" {
+		t.Errorf("Unexpected commits for PAY-1: %+v", matched)
+	}
+	if matched := CommitsForTicket(commits, " pay-1"); len(matched) != 3 {
+		t.Errorf("Expected the ticket to match ignoring case, got %+v", matched)
+	}
+}
@@ -0,0 +1,144 @@
+package stats
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"git-watcher/pkg/analyzer"
+
+	"gopkg.in/yaml.v3"
+)
+
+// TimeZone decides the wall clock time-bucketed statistics see for each
+// commit. Authors maps an author name or email to their zone; everyone else
+// uses Location, or the offset the commit was recorded with when Location
+// is nil. A nil *TimeZone keeps recorded offsets for everyone.
+type TimeZone struct {
+	Location *time.Location
+	Authors  map[string]*time.Location
+}
+
+type timeZoneConfig struct {
+	Default string            `yaml:"default"`
+	Authors map[string]string `yaml:"authors"`
+}
+
+// LoadTimeZone builds a TimeZone from a --timezone value ("author" for the
+// recorded offsets or an IANA zone name) and an optional YAML file of
+// per-author zones:
+//
+//	default: Europe/Berlin
+//	authors:
+//	  Alice: America/New_York
+//	  bob@example.com: Asia/Shanghai
+//
+// It returns nil when every commit keeps its recorded offset.
+func LoadTimeZone(spec, configPath string) (*TimeZone, error) {
+	tz := &TimeZone{Authors: make(map[string]*time.Location)}
+
+	if spec != "" && spec != "author" {
+		loc, err := time.LoadLocation(spec)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timezone %q: %w", spec, err)
+		}
+		tz.Location = loc
+	}
+
+	if configPath != "" {
+		data, err := os.ReadFile(configPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read timezone config: %w", err)
+		}
+		var cfg timeZoneConfig
+		if err := yaml.Unmarshal(data, &cfg); err != nil {
+			return nil, fmt.Errorf("failed to parse timezone config %s: %w", configPath, err)
+		}
+		if cfg.Default != "" && tz.Location == nil {
+			loc, err := time.LoadLocation(cfg.Default)
+			if err != nil {
+				return nil, fmt.Errorf("invalid default timezone %q in %s: %w", cfg.Default, configPath, err)
+			}
+			tz.Location = loc
+		}
+		for author, name := range cfg.Authors {
+			loc, err := time.LoadLocation(name)
+			if err != nil {
+				return nil, fmt.Errorf("invalid timezone %q for %s in %s: %w", name, author, configPath, err)
+			}
+			tz.Authors[strings.ToLower(author)] = loc
+		}
+	}
+
+	if tz.Location == nil && len(tz.Authors) == 0 {
+		return nil, nil
+	}
+	return tz, nil
+}
+
+// In returns the commit date on the wall clock chosen for its author.
+func (tz *TimeZone) In(commit analyzer.CommitInfo) time.Time {
+	if tz == nil {
+		return commit.Date
+	}
+	if loc, ok := tz.Authors[strings.ToLower(commit.Author)]; ok {
+		return commit.Date.In(loc)
+	}
+	if loc, ok := tz.Authors[strings.ToLower(commit.Email)]; ok {
+		return commit.Date.In(loc)
+	}
+	if tz.Location != nil {
+		return commit.Date.In(tz.Location)
+	}
+	return commit.Date
+}
+
+// Localize returns a copy of commits with every date converted by In. Tag
+// dates, which have no author, are converted to Location when it is set.
+func (tz *TimeZone) Localize(commits []analyzer.CommitInfo) []analyzer.CommitInfo {
+	if tz == nil {
+		return commits
+	}
+	local := make([]analyzer.CommitInfo, len(commits))
+	for i, commit := range commits {
+		local[i] = commit
+		local[i].Date = tz.In(commit)
+		if tz.Location != nil && len(commit.Tags) > 0 {
+			local[i].Tags = make([]analyzer.Tag, len(commit.Tags))
+			for j, tag := range commit.Tags {
+				tag.Date = tag.Date.In(tz.Location)
+				local[i].Tags[j] = tag
+			}
+		}
+	}
+	return local
+}
+
+// recordedTimeStatistic is implemented by statistics that must see commit
+// dates with the offsets they were recorded with rather than localized ones.
+type recordedTimeStatistic interface {
+	usesRecordedTime()
+}
+
+// UTCOffsetsByAuthor reports how often each author recorded each UTC
+// offset, which exposes travel, mixed-timezone teams and misconfigured
+// clocks.
+type UTCOffsetsByAuthor struct{}
+
+func (u *UTCOffsetsByAuthor) Name() string {
+	return "utc_offsets_by_author"
+}
+
+func (u *UTCOffsetsByAuthor) usesRecordedTime() {}
+
+func (u *UTCOffsetsByAuthor) Calculate(commits []analyzer.CommitInfo) interface{} {
+	offsets := make(map[string]map[string]int)
+	for _, commit := range commits {
+		if offsets[commit.Author] == nil {
+			offsets[commit.Author] = make(map[string]int)
+		}
+		offsets[commit.Author][commit.Date.Format("-07:00")]++
+	}
+	return offsets
+}
@@ -0,0 +1,100 @@
+package stats
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"git-watcher/pkg/analyzer"
+)
+
+func TestLoadTimeZone(t *testing.T) {
+	tz, err := LoadTimeZone("author", "")
+	if err != nil || tz != nil {
+		t.Errorf("Expected recorded offsets for the author timezone, got %v, %v", tz, err)
+	}
+
+	if _, err := LoadTimeZone("Mars/Olympus", ""); err == nil {
+		t.Errorf("Expected an error for an unknown zone")
+	}
+
+	path := filepath.Join(t.TempDir(), "timezones.yaml")
+	config := "default: UTC\nauthors:\n  Alice: Asia/Shanghai\n  bob@example.com: America/New_York\n"
+	if err := os.WriteFile(path, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tz, err = LoadTimeZone("author", path)
+	if err != nil {
+		t.Fatalf("Failed to load timezone config: %v", err)
+	}
+
+	date := time.Date(2024, 1, 1, 12, 0, 0, 0, time.FixedZone("UTC+2", 2*3600))
+	if got := tz.In(analyzer.CommitInfo{Author: "alice", Date: date}).Hour(); got != 18 {
+		t.Errorf("Expected Alice's commit at 18:00 in Shanghai, got %d", got)
+	}
+	if got := tz.In(analyzer.CommitInfo{Author: "Bob", Email: "bob@example.com", Date: date}).Hour(); got != 5 {
+		t.Errorf("Expected Bob's commit at 05:00 in New York, got %d", got)
+	}
+	if got := tz.In(analyzer.CommitInfo{Author: "Carol", Date: date}).Hour(); got != 10 {
+		t.Errorf("Expected Carol's commit at 10:00 UTC, got %d", got)
+	}
+}
+
+func TestCalculateAllRespectsTimeZone(t *testing.T) {
+	shanghai, err := time.LoadLocation("Asia/Shanghai")
+	if err != nil {
+		t.Fatal(err)
+	}
+	commits := []analyzer.CommitInfo{
+		// 16:00 UTC is 00:00 the next day in Shanghai
+		{Author: "Alice", Date: time.Date(2024, 1, 5, 16, 0, 0, 0, time.UTC)},
+		{Author: "Alice", Date: time.Date(2024, 1, 5, 18, 0, 0, 0, shanghai)},
+	}
+
+	opts := DefaultOptions()
+	opts.TimeZone = &TimeZone{Location: shanghai}
+	results := NewStatsCalculatorWithOptions(opts).CalculateAll(commits)
+
+	lateNight := results["late_night_commits"].(map[string]interface{})
+	if lateNight["total"].(int) != 1 {
+		t.Errorf("Expected 1 late-night commit in Shanghai time, got %v", lateNight["total"])
+	}
+	weekend := results["weekend_commits"].(map[string]interface{})
+	if weekend["total"].(int) != 1 {
+		t.Errorf("Expected the Friday 16:00 UTC commit on Saturday in Shanghai, got %v", weekend["total"])
+	}
+
+	offsets := results["utc_offsets_by_author"].(map[string]map[string]int)
+	if offsets["Alice"]["+00:00"] != 1 || offsets["Alice"]["+08:00"] != 1 {
+		t.Errorf("Expected recorded offsets to be reported, got %v", offsets["Alice"])
+	}
+}
+
+func TestLocalizeTags(t *testing.T) {
+	shanghai, err := time.LoadLocation("Asia/Shanghai")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// tagged at 20:00 UTC on 1 and 2 March, the next mornings in Shanghai
+	commits := []analyzer.CommitInfo{
+		{Author: "Alice", Date: time.Date(2024, 3, 1, 19, 0, 0, 0, time.UTC),
+			Tags: []analyzer.Tag{{Name: "v1.0.0", Date: time.Date(2024, 3, 1, 20, 0, 0, 0, time.UTC)}}},
+		{Author: "Alice", Date: time.Date(2024, 3, 2, 19, 0, 0, 0, time.UTC),
+			Tags: []analyzer.Tag{{Name: "v1.1.0", Date: time.Date(2024, 3, 2, 20, 0, 0, 0, time.FixedZone("", -5*60*60))}}},
+	}
+	local := (&TimeZone{Location: shanghai}).Localize(commits)
+	if got := local[0].Tags[0].Date.Format("2006-01-02 15:04"); got != "2024-03-02 04:00" {
+		t.Errorf("Expected the tag date in Shanghai time, got %s", got)
+	}
+	if commits[0].Tags[0].Date.Location() != time.UTC {
+		t.Errorf("Expected Localize to leave the original tags alone")
+	}
+
+	opts := DefaultOptions()
+	opts.TimeZone = &TimeZone{Location: shanghai}
+	report := NewStatsCalculatorWithOptions(opts).CalculateAll(commits)["releases"].(ReleaseReport)
+	if len(report.Releases) != 2 || report.Releases[0].Date != "2024-03-03" || report.Releases[1].Date != "2024-03-02" {
+		t.Errorf("Expected release dates in Shanghai time, got %+v", report.Releases)
+	}
+}
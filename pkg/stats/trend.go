@@ -0,0 +1,154 @@
+package stats
+
+import (
+	"fmt"
+	"time"
+
+	"git-watcher/pkg/analyzer"
+	"git-watcher/pkg/policy"
+)
+
+type TrendBucket string
+
+const (
+	BucketDay     TrendBucket = "day"
+	BucketWeek    TrendBucket = "week"
+	BucketMonth   TrendBucket = "month"
+	BucketQuarter TrendBucket = "quarter"
+)
+
+// TrendBuckets lists the supported bucket sizes, smallest first.
+var TrendBuckets = []TrendBucket{BucketDay, BucketWeek, BucketMonth, BucketQuarter}
+
+func ParseTrendBucket(s string) (TrendBucket, error) {
+	for _, b := range TrendBuckets {
+		if string(b) == s {
+			return b, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported trend bucket: %s (day|week|month|quarter)", s)
+}
+
+// start returns the first instant of the bucket containing t. Buckets follow
+// the commit's calendar date, weeks start on Monday.
+func (b TrendBucket) start(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch b {
+	case BucketWeek:
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case BucketMonth:
+		return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+	case BucketQuarter:
+		month := (day.Month()-1)/3*3 + 1
+		return time.Date(day.Year(), month, 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return day
+	}
+}
+
+func (b TrendBucket) next(start time.Time) time.Time {
+	switch b {
+	case BucketWeek:
+		return start.AddDate(0, 0, 7)
+	case BucketMonth:
+		return start.AddDate(0, 1, 0)
+	case BucketQuarter:
+		return start.AddDate(0, 3, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+func (b TrendBucket) label(start time.Time) string {
+	switch b {
+	case BucketMonth:
+		return start.Format("2006-01")
+	case BucketQuarter:
+		return fmt.Sprintf("%d-Q%d", start.Year(), (int(start.Month())-1)/3+1)
+	default:
+		return start.Format("2006-01-02")
+	}
+}
+
+// Trend buckets commits over time so lifetime totals can be followed as
+// series: commits, lines, active authors and the share of late-night and
+// weekend work. Empty buckets between the first and last commit are kept so
+// the series are evenly spaced.
+type Trend struct {
+	Bucket   TrendBucket
+	Policies *policy.Set
+}
+
+type TrendPoint struct {
+	Start          string  `json:"start"`
+	Commits        int     `json:"commits"`
+	Lines          int64   `json:"lines"`
+	ActiveAuthors  int     `json:"active_authors"`
+	LateNightShare float64 `json:"late_night_share"`
+	WeekendShare   float64 `json:"weekend_share"`
+}
+
+type TrendReport struct {
+	Bucket TrendBucket  `json:"bucket"`
+	Series []TrendPoint `json:"series"`
+}
+
+func (t *Trend) Name() string {
+	return "trend"
+}
+
+func (t *Trend) Calculate(commits []analyzer.CommitInfo) interface{} {
+	bucket := t.Bucket
+	if bucket == "" {
+		bucket = BucketMonth
+	}
+	report := TrendReport{Bucket: bucket, Series: []TrendPoint{}}
+	if len(commits) == 0 {
+		return report
+	}
+
+	type acc struct {
+		commits, lateNight, weekend int
+		lines                       int64
+		authors                     map[string]bool
+	}
+	buckets := make(map[time.Time]*acc)
+	var first, last time.Time
+	for i, commit := range commits {
+		start := bucket.start(commit.Date)
+		if i == 0 || start.Before(first) {
+			first = start
+		}
+		if i == 0 || start.After(last) {
+			last = start
+		}
+		a := buckets[start]
+		if a == nil {
+			a = &acc{authors: make(map[string]bool)}
+			buckets[start] = a
+		}
+		a.commits++
+		a.lines += commit.LineCount
+		a.authors[commit.Author] = true
+		p := t.Policies.For(commit.Author, commit.Email)
+		if p.IsLateNight(commit.Date) {
+			a.lateNight++
+		}
+		if p.IsWeekend(commit.Date) {
+			a.weekend++
+		}
+	}
+
+	for start := first; !start.After(last); start = bucket.next(start) {
+		point := TrendPoint{Start: bucket.label(start)}
+		if a := buckets[start]; a != nil {
+			point.Commits = a.commits
+			point.Lines = a.lines
+			point.ActiveAuthors = len(a.authors)
+			point.LateNightShare = float64(a.lateNight) / float64(a.commits)
+			point.WeekendShare = float64(a.weekend) / float64(a.commits)
+		}
+		report.Series = append(report.Series, point)
+	}
+	return report
+}
@@ -0,0 +1,50 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"git-watcher/pkg/analyzer"
+)
+
+func TestTrend(t *testing.T) {
+	commits := []analyzer.CommitInfo{
+		{Author: "Alice", LineCount: 10, Date: time.Date(2024, 1, 6, 23, 30, 0, 0, time.UTC)}, // Saturday, late night
+		{Author: "Bob", LineCount: 5, Date: time.Date(2024, 1, 8, 10, 0, 0, 0, time.UTC)},     // Monday
+		{Author: "Alice", LineCount: 1, Date: time.Date(2024, 3, 15, 10, 0, 0, 0, time.UTC)},  // Friday
+	}
+
+	month := (&Trend{Bucket: BucketMonth}).Calculate(commits).(TrendReport)
+	if len(month.Series) != 3 {
+		t.Fatalf("Expected 3 monthly buckets including the empty February, got %d", len(month.Series))
+	}
+	jan := month.Series[0]
+	if jan.Start != "2024-01" || jan.Commits != 2 || jan.Lines != 15 || jan.ActiveAuthors != 2 {
+		t.Errorf("Unexpected January bucket: %+v", jan)
+	}
+	if jan.LateNightShare != 0.5 || jan.WeekendShare != 0.5 {
+		t.Errorf("Expected half of January to be late-night and weekend work, got %+v", jan)
+	}
+	if month.Series[1].Start != "2024-02" || month.Series[1].Commits != 0 {
+		t.Errorf("Expected an empty February bucket, got %+v", month.Series[1])
+	}
+
+	week := (&Trend{Bucket: BucketWeek}).Calculate(commits).(TrendReport)
+	if week.Series[0].Start != "2024-01-01" || week.Series[0].Commits != 1 {
+		t.Errorf("Expected the Saturday commit in the week starting 2024-01-01, got %+v", week.Series[0])
+	}
+
+	quarter := (&Trend{Bucket: BucketQuarter}).Calculate(commits).(TrendReport)
+	if len(quarter.Series) != 1 || quarter.Series[0].Start != "2024-Q1" {
+		t.Errorf("Unexpected quarterly series: %+v", quarter.Series)
+	}
+}
+
+func TestParseTrendBucket(t *testing.T) {
+	if b, err := ParseTrendBucket("week"); err != nil || b != BucketWeek {
+		t.Errorf("Expected week bucket, got %v, %v", b, err)
+	}
+	if _, err := ParseTrendBucket("year"); err == nil {
+		t.Errorf("Expected an error for an unsupported bucket")
+	}
+}
@@ -0,0 +1,200 @@
+package stats
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"git-watcher/pkg/analyzer"
+	"git-watcher/pkg/policy"
+)
+
+// WellbeingThresholds are the levels at which a wellbeing signal flags an
+// author.
+type WellbeingThresholds struct {
+	// OutOfHoursShare is the share of commits outside working hours.
+	OutOfHoursShare float64 `json:"out_of_hours_share"`
+	// Streak is the number of consecutive days with commits.
+	Streak int `json:"streak"`
+	// TimeOffCommits counts commits on vacations and public holidays.
+	TimeOffCommits int `json:"time_off_commits"`
+	// RecentIncrease is how far the out-of-hours share of the last
+	// RecentDays may rise above the author's earlier share.
+	RecentIncrease float64 `json:"recent_increase"`
+	RecentDays     int     `json:"recent_days"`
+}
+
+func DefaultWellbeingThresholds() WellbeingThresholds {
+	return WellbeingThresholds{
+		OutOfHoursShare: 0.3,
+		Streak:          10,
+		TimeOffCommits:  3,
+		RecentIncrease:  0.15,
+		RecentDays:      28,
+	}
+}
+
+const (
+	WellbeingOK     = "ok"
+	WellbeingWatch  = "watch"
+	WellbeingAtRisk = "at_risk"
+)
+
+// Wellbeing combines out-of-hours work, commit streaks, commits on time off
+// and the recent trend against each author's own baseline into a score from
+// 0 (every signal at or past its threshold) to 100. Authors with one flagged
+// signal are to watch, two or more are at risk of burnout. A zero threshold
+// disables its signal.
+type Wellbeing struct {
+	Policies   *policy.Set
+	Thresholds WellbeingThresholds
+}
+
+type AuthorWellbeing struct {
+	Team            string   `json:"team,omitempty"`
+	Commits         int      `json:"commits"`
+	OutOfHoursShare float64  `json:"out_of_hours_share"`
+	LongestStreak   int      `json:"longest_streak"`
+	TimeOffCommits  int      `json:"time_off_commits"`
+	RecentShare     float64  `json:"recent_out_of_hours_share"`
+	BaselineShare   float64  `json:"baseline_out_of_hours_share"`
+	Score           int      `json:"score"`
+	Flags           []string `json:"flags"`
+	Level           string   `json:"level"`
+}
+
+type TeamWellbeing struct {
+	Members         int      `json:"members"`
+	Commits         int      `json:"commits"`
+	OutOfHoursShare float64  `json:"out_of_hours_share"`
+	Score           int      `json:"score"`
+	AtRisk          []string `json:"at_risk"`
+}
+
+type WellbeingReport struct {
+	Thresholds WellbeingThresholds         `json:"thresholds"`
+	Authors    map[string]*AuthorWellbeing `json:"authors"`
+	Teams      map[string]*TeamWellbeing   `json:"teams"`
+}
+
+func (w *Wellbeing) Name() string {
+	return "wellbeing"
+}
+
+func (w *Wellbeing) Calculate(commits []analyzer.CommitInfo) interface{} {
+	th := w.Thresholds
+	if th == (WellbeingThresholds{}) {
+		th = DefaultWellbeingThresholds()
+	}
+	report := WellbeingReport{
+		Thresholds: th,
+		Authors:    make(map[string]*AuthorWellbeing),
+		Teams:      make(map[string]*TeamWellbeing),
+	}
+	if len(commits) == 0 {
+		return report
+	}
+
+	latest := commits[0].Date
+	for _, commit := range commits {
+		if commit.Date.After(latest) {
+			latest = commit.Date
+		}
+	}
+	recentSince := latest.AddDate(0, 0, -th.RecentDays)
+
+	type acc struct {
+		commits, outOfHours, timeOff int
+		recent, recentOut            int
+		days                         []time.Time
+	}
+	byAuthor := make(map[string]*acc)
+	for _, commit := range commits {
+		a := byAuthor[commit.Author]
+		if a == nil {
+			a = &acc{}
+			byAuthor[commit.Author] = a
+			report.Authors[commit.Author] = &AuthorWellbeing{Team: w.Policies.TeamOf(commit.Author, commit.Email)}
+		}
+		p := w.Policies.For(commit.Author, commit.Email)
+		out := p.IsOutOfHours(commit.Date)
+		a.commits++
+		a.days = append(a.days, commit.Date)
+		if out {
+			a.outOfHours++
+		}
+		if _, ok := p.TimeOff(commit.Date); ok {
+			a.timeOff++
+		}
+		if commit.Date.After(recentSince) {
+			a.recent++
+			if out {
+				a.recentOut++
+			}
+		}
+	}
+
+	for author, a := range byAuthor {
+		wb := report.Authors[author]
+		wb.Commits = a.commits
+		wb.OutOfHoursShare = ratio(float64(a.outOfHours), float64(a.commits))
+		wb.LongestStreak = longestStreak(commitDays(a.days))
+		wb.TimeOffCommits = a.timeOff
+		if baseline := a.commits - a.recent; a.recent > 0 && baseline > 0 {
+			wb.RecentShare = ratio(float64(a.recentOut), float64(a.recent))
+			wb.BaselineShare = ratio(float64(a.outOfHours-a.recentOut), float64(baseline))
+		}
+
+		increase := wb.RecentShare - wb.BaselineShare
+		signals := []struct {
+			flag     string
+			pressure float64
+		}{
+			{"out_of_hours", ratio(wb.OutOfHoursShare, th.OutOfHoursShare)},
+			{"streak", ratio(float64(wb.LongestStreak), float64(th.Streak))},
+			{"time_off", ratio(float64(wb.TimeOffCommits), float64(th.TimeOffCommits))},
+			{"recent_increase", ratio(increase, th.RecentIncrease)},
+		}
+		var pressure float64
+		wb.Flags = []string{}
+		for _, s := range signals {
+			p := math.Max(0, math.Min(s.pressure, 1))
+			pressure += p
+			if p >= 1 {
+				wb.Flags = append(wb.Flags, s.flag)
+			}
+		}
+		wb.Score = int(math.Round(100 * (1 - pressure/float64(len(signals)))))
+		switch {
+		case len(wb.Flags) >= 2:
+			wb.Level = WellbeingAtRisk
+		case len(wb.Flags) == 1:
+			wb.Level = WellbeingWatch
+		default:
+			wb.Level = WellbeingOK
+		}
+
+		if wb.Team == "" {
+			continue
+		}
+		team := report.Teams[wb.Team]
+		if team == nil {
+			team = &TeamWellbeing{AtRisk: []string{}}
+			report.Teams[wb.Team] = team
+		}
+		team.Members++
+		team.Commits += a.commits
+		team.OutOfHoursShare += float64(a.outOfHours)
+		team.Score += wb.Score
+		if wb.Level == WellbeingAtRisk {
+			team.AtRisk = append(team.AtRisk, author)
+		}
+	}
+
+	for _, team := range report.Teams {
+		team.OutOfHoursShare /= float64(team.Commits)
+		team.Score = int(math.Round(float64(team.Score) / float64(team.Members)))
+		sort.Strings(team.AtRisk)
+	}
+	return report
+}
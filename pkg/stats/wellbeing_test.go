@@ -0,0 +1,67 @@
+package stats
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"git-watcher/pkg/analyzer"
+	"git-watcher/pkg/policy"
+)
+
+func TestWellbeing(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	config := "teams:\n  core:\n    members: [Alice, Bob]\nauthors:\n  Bob:\n    vacations:\n      - date: 2024-03-04\n        end: 2024-03-08\n"
+	if err := os.WriteFile(path, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	policies, err := policy.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var commits []analyzer.CommitInfo
+	// Alice: weekday office hours through February
+	for day := 1; day <= 29; day++ {
+		date := time.Date(2024, 2, day, 10, 0, 0, 0, time.UTC)
+		if date.Weekday() != time.Saturday && date.Weekday() != time.Sunday {
+			commits = append(commits, analyzer.CommitInfo{Author: "Alice", Date: date})
+		}
+	}
+	// Bob: office hours in January, then every evening for two weeks,
+	// including his vacation
+	for day := 8; day <= 12; day++ {
+		commits = append(commits, analyzer.CommitInfo{Author: "Bob", Date: time.Date(2024, 1, day, 11, 0, 0, 0, time.UTC)})
+	}
+	for day := 1; day <= 14; day++ {
+		commits = append(commits, analyzer.CommitInfo{Author: "Bob", Date: time.Date(2024, 3, day, 21, 0, 0, 0, time.UTC)})
+	}
+
+	stat := &Wellbeing{Policies: policies, Thresholds: DefaultWellbeingThresholds()}
+	report := stat.Calculate(commits).(WellbeingReport)
+
+	alice := report.Authors["Alice"]
+	if alice.Level != WellbeingOK || alice.Score < 80 || alice.OutOfHoursShare != 0 {
+		t.Errorf("Expected Alice to be fine, got %+v", alice)
+	}
+	if alice.LongestStreak != 5 {
+		t.Errorf("Expected Alice's longest streak to be a working week, got %d", alice.LongestStreak)
+	}
+
+	bob := report.Authors["Bob"]
+	if bob.Level != WellbeingAtRisk {
+		t.Errorf("Expected Bob to be at risk, got %+v", bob)
+	}
+	if bob.LongestStreak != 14 || bob.TimeOffCommits != 5 || bob.BaselineShare != 0 || bob.RecentShare != 1 {
+		t.Errorf("Unexpected signals for Bob: %+v", bob)
+	}
+	if len(bob.Flags) != 4 || bob.Score != 0 {
+		t.Errorf("Expected every signal flagged for Bob, got %v (score %d)", bob.Flags, bob.Score)
+	}
+
+	core := report.Teams["core"]
+	if core == nil || core.Members != 2 || len(core.AtRisk) != 1 || core.AtRisk[0] != "Bob" {
+		t.Errorf("Unexpected team wellbeing: %+v", core)
+	}
+}
@@ -9,25 +9,67 @@ import (
 	"time"
 
 	"git-watcher/pkg/analyzer"
+	"git-watcher/pkg/stats"
 	"git-watcher/ui"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 )
 
-func StartTUI(rootPath string) error {
+func StartTUI(rootPath string, opts stats.Options, branchOpts analyzer.BranchOptions) error {
 	app := tview.NewApplication()
-	ctrl := ui.NewController(rootPath)
+	ctrl := ui.NewController(rootPath, opts)
+	ctrl.Branches = branchOpts
 
 	repos := tview.NewList()
-	overview := tview.NewTextView().SetDynamicColors(true)
-	overview.SetScrollable(true)
-	commits := tview.NewTextView().SetDynamicColors(true)
-	commits.SetScrollable(true)
-	authors := tview.NewTextView().SetDynamicColors(true)
-	authors.SetScrollable(true)
-	timeline := tview.NewTextView().SetDynamicColors(true)
-	timeline.SetScrollable(true)
+	newPage := func() *tview.TextView {
+		tv := tview.NewTextView().SetDynamicColors(true)
+		tv.SetScrollable(true)
+		return tv
+	}
+	overview := newPage()
+	commits := newPage()
+	authors := newPage()
+	timeline := newPage()
+	hotspots := newPage()
+	coupling := newPage()
+	heatmap := newPage()
+	calendar := newPage()
+	trend := newPage()
+	cohorts := newPage()
+	releases := newPage()
+	branches := newPage()
+	compare := newPage()
+	couplingFiles := tview.NewList().ShowSecondaryText(false)
+	couplingPage := tview.NewFlex().
+		AddItem(couplingFiles, 0, 1, true).
+		AddItem(coupling, 0, 1, false)
+	pageOrder := []string{"overview", "commits", "authors", "timeline", "hotspots", "coupling", "heatmap", "calendar", "trend", "cohorts", "releases", "branches", "compare"}
+	views := map[string]*tview.TextView{
+		"overview": overview,
+		"commits":  commits,
+		"authors":  authors,
+		"timeline": timeline,
+		"hotspots": hotspots,
+		"coupling": coupling,
+		"heatmap":  heatmap,
+		"calendar": calendar,
+		"trend":    trend,
+		"cohorts":  cohorts,
+		"releases": releases,
+		"branches": branches,
+		"compare":  compare,
+	}
+	// pages whose root is not their scrollable text view
+	pages := map[string]tview.Primitive{
+		"coupling": couplingPage,
+	}
+	pageFor := func(name string) tview.Primitive {
+		if p, ok := pages[name]; ok {
+			return p
+		}
+		return views[name]
+	}
 
 	statusView := tview.NewTextView().SetDynamicColors(true)
 	statusView.SetBorder(true)
@@ -52,13 +94,22 @@ func StartTUI(rootPath string) error {
 	helpBar.AddItem(mk("2 Commits"), 0, 1, false)
 	helpBar.AddItem(mk("3 Authors"), 0, 1, false)
 	helpBar.AddItem(mk("4 Timeline"), 0, 1, false)
+	helpBar.AddItem(mk("5 Hotspots"), 0, 1, false)
+	helpBar.AddItem(mk("6 Coupling"), 0, 1, false)
+	helpBar.AddItem(mk("7 Heatmap"), 0, 1, false)
+	helpBar.AddItem(mk("8 Calendar"), 0, 1, false)
+	helpBar.AddItem(mk("9 Trends"), 0, 1, false)
+	helpBar.AddItem(mk("0 Cohorts"), 0, 1, false)
+	helpBar.AddItem(mk("v Releases"), 0, 1, false)
+	helpBar.AddItem(mk("b Branches"), 0, 1, false)
+	helpBar.AddItem(mk("f Local work"), 0, 1, false)
+	helpBar.AddItem(mk("m Mark/c Compare"), 0, 1, false)
 
 	right := tview.NewPages()
 	right.SetBorder(true)
-	right.AddPage("overview", overview, true, true)
-	right.AddPage("commits", commits, true, false)
-	right.AddPage("authors", authors, true, false)
-	right.AddPage("timeline", timeline, true, false)
+	for i, name := range pageOrder {
+		right.AddPage(name, pageFor(name), true, i == 0)
+	}
 
 	content := tview.NewFlex().AddItem(repos, 30, 0, true).AddItem(right, 0, 1, false)
 	layout := tview.NewFlex().SetDirection(tview.FlexRow).
@@ -67,11 +118,14 @@ func StartTUI(rootPath string) error {
 		AddItem(helpBar, 3, 0, false)
 
 	selectedRepo := ""
+	// listedRepos are the repositories shown in the list, all of them or
+	// only those with local work
+	var listedRepos []string
+	onlyLocalWork := false
+	// marked repositories are compared on the compare page
+	marked := map[string]bool{}
 	focusOnRepos := false
-	scrollOverviewY := 0
-	scrollCommitsY := 0
-	scrollAuthorsY := 0
-	scrollTimelineY := 0
+	scrollY := map[string]int{}
 	// removed author filter
 	auto := false
 	var ticker *time.Ticker
@@ -82,19 +136,23 @@ func StartTUI(rootPath string) error {
 		if selectedRepo == "" {
 			fmt.Fprintln(b, "No repository selected")
 		} else {
-			stats := ctrl.State.StatsByRepo[selectedRepo]
+			repoStats := ctrl.State.StatsByRepo[selectedRepo]
 			commitsList := ctrl.State.CommitsByRepo[selectedRepo]
-			fmt.Fprintf(b, "Repo: %s\n", selectedRepo)
+			fmt.Fprintf(b, "Repo: %s\n", ui.RepoName(selectedRepo))
 			fmt.Fprintf(b, "Total commits: %d\n", len(commitsList))
-			if v := stats["late_night_commits"]; v != nil {
+			if v := repoStats["late_night_commits"]; v != nil {
 				m := v.(map[string]interface{})
 				fmt.Fprintf(b, "Late-night: %v\n", m["total"])
 			}
-			if v := stats["weekend_commits"]; v != nil {
+			if v := repoStats["weekend_commits"]; v != nil {
 				m := v.(map[string]interface{})
 				fmt.Fprintf(b, "Weekend: %v\n", m["total"])
 			}
-			if v := stats["commit_activity_by_hour"]; v != nil {
+			if v := repoStats["holiday_commits"]; v != nil {
+				m := v.(map[string]interface{})
+				fmt.Fprintf(b, "Holiday: %v\n", m["total"])
+			}
+			if v := repoStats["commit_activity_by_hour"]; v != nil {
 				m := v.(map[int]int)
 				fmt.Fprint(b, "Hourly: ")
 				for h := 0; h < 24; h++ {
@@ -102,6 +160,9 @@ func StartTUI(rootPath string) error {
 				}
 				fmt.Fprintln(b)
 			}
+			if v := repoStats["commit_sizes"]; v != nil {
+				writeCommitSizes(b, v.(stats.CommitSizeReport))
+			}
 		}
 		overview.SetText(b.String())
 	}
@@ -109,13 +170,27 @@ func StartTUI(rootPath string) error {
 	sortAscCommits := false
 	sortAscAuthors := false
 	sortAscTimeline := true
+	hotspotSort := stats.HotspotSortScore
+	heatmapAuthor := 0
+	calendarAuthor := 0
+	var calendarCursor time.Time
+	commitsDay := ""
+	trendBucket := opts.TrendBucket
 
 	renderCommits := func() {
 		b := &strings.Builder{}
 		if selectedRepo == "" {
 			fmt.Fprintln(b, "No repository selected")
 		} else {
-			list := append([]analyzer.CommitInfo(nil), ctrl.State.CommitsByRepo[selectedRepo]...)
+			var list []analyzer.CommitInfo
+			for _, c := range ctrl.State.CommitsByRepo[selectedRepo] {
+				if commitsDay == "" || c.Date.Format(dayFormat) == commitsDay {
+					list = append(list, c)
+				}
+			}
+			if commitsDay != "" {
+				fmt.Fprintf(b, "[yellow]Commits on %s: %d (x to clear)[-]\n\n", commitsDay, len(list))
+			}
 			if sortAscCommits {
 				sort.Slice(list, func(i, j int) bool { return list[i].Date.Before(list[j].Date) })
 			} else {
@@ -152,8 +227,17 @@ func StartTUI(rootPath string) error {
 					sort.Slice(arr, func(i, j int) bool { return arr[i].V > arr[j].V })
 				}
 				fmt.Fprintln(b, "Commits by author:")
-				for _, it := range arr {
-					fmt.Fprintf(b, "%s: %d\n", it.A, it.V)
+				streaks, _ := ctrl.State.StatsByRepo[selectedRepo]["streaks"].(map[string]stats.AuthorStreaks)
+				if streaks == nil {
+					for _, it := range arr {
+						fmt.Fprintf(b, "%s: %d\n", it.A, it.V)
+					}
+				} else {
+					names := make([]string, len(arr))
+					for i, it := range arr {
+						names[i] = it.A
+					}
+					writeAuthorActivity(b, names, m, streaks)
 				}
 			}
 
@@ -197,6 +281,10 @@ func StartTUI(rootPath string) error {
 					fmt.Fprintf(b, "%s: %s %d\n", it.A, draw(it.V), it.V)
 				}
 			}
+
+			if wb := ctrl.State.StatsByRepo[selectedRepo]["wellbeing"]; wb != nil {
+				writeWellbeing(b, wb.(stats.WellbeingReport))
+			}
 		}
 		authors.SetText(b.String())
 	}
@@ -243,6 +331,269 @@ func StartTUI(rootPath string) error {
 		timeline.SetText(b.String())
 	}
 
+	renderHotspots := func() {
+		b := &strings.Builder{}
+		if selectedRepo == "" {
+			fmt.Fprintln(b, "No repository selected")
+		} else if v := ctrl.State.StatsByRepo[selectedRepo]["hotspots"]; v != nil {
+			writeHotspots(b, v.(stats.HotspotReport), hotspotSort)
+		}
+		hotspots.SetText(b.String())
+	}
+
+	var couplingReport stats.CouplingReport
+	var couplingList []string
+	renderCouplingPartners := func(file string) {
+		b := &strings.Builder{}
+		partners := couplingReport.Partners(file)
+		fmt.Fprintf(b, "Files changed together with [yellow]%s[-]:\n\n", tview.Escape(file))
+		fmt.Fprintf(b, "%7s %7s %10s  %s\n", "Degree", "Support", "Confidence", "Partner")
+		for _, p := range partners {
+			fmt.Fprintf(b, "%6.1f%% %7d %9.1f%%  %s\n", p.Degree*100, p.Support, p.Confidence*100, tview.Escape(p.File))
+		}
+		coupling.SetText(b.String())
+		coupling.ScrollToBeginning()
+	}
+
+	renderCoupling := func() {
+		couplingReport = stats.CouplingReport{}
+		couplingList = nil
+		couplingFiles.Clear()
+		if selectedRepo == "" {
+			coupling.SetText("No repository selected")
+			return
+		}
+		if v := ctrl.State.StatsByRepo[selectedRepo]["temporal_coupling"]; v != nil {
+			couplingReport = v.(stats.CouplingReport)
+			couplingList = couplingReport.Files()
+		}
+		if len(couplingList) == 0 {
+			coupling.SetText("No files are frequently changed together")
+			return
+		}
+		for _, file := range couplingList {
+			couplingFiles.AddItem(tview.Escape(file), "", 0, nil)
+		}
+		renderCouplingPartners(couplingList[0])
+	}
+
+	couplingFiles.SetChangedFunc(func(i int, mainText, secondaryText string, shortcut rune) {
+		if i >= 0 && i < len(couplingList) {
+			renderCouplingPartners(couplingList[i])
+		}
+	})
+
+	renderHeatmap := func() {
+		b := &strings.Builder{}
+		if selectedRepo == "" {
+			fmt.Fprintln(b, "No repository selected")
+			heatmap.SetText(b.String())
+			return
+		}
+		list := ctrl.State.CommitsByRepo[selectedRepo]
+		authorNames := authorsByCommits(list)
+		if heatmapAuthor > len(authorNames) {
+			heatmapAuthor = 0
+		}
+		title := "all authors"
+		if heatmapAuthor > 0 {
+			title = authorNames[heatmapAuthor-1]
+			var filtered []analyzer.CommitInfo
+			for _, c := range list {
+				if c.Author == title {
+					filtered = append(filtered, c)
+				}
+			}
+			list = filtered
+		}
+		hm := (&stats.WeekdayHourHeatmap{}).Calculate(list).(stats.Heatmap)
+		writeHeatmap(b, hm, title)
+		heatmap.SetText(b.String())
+	}
+
+	renderCalendar := func() {
+		b := &strings.Builder{}
+		if selectedRepo == "" {
+			fmt.Fprintln(b, "No repository selected")
+			calendar.SetText(b.String())
+			return
+		}
+		v := ctrl.State.StatsByRepo[selectedRepo]["contribution_calendar"]
+		if v == nil {
+			calendar.SetText(b.String())
+			return
+		}
+		cal := v.(stats.Calendar)
+		start, end := calendarRange(cal)
+		if calendarCursor.Before(start) || calendarCursor.After(end) {
+			calendarCursor = end
+		}
+
+		list := ctrl.State.CommitsByRepo[selectedRepo]
+		authorNames := authorsByCommits(list)
+		if calendarAuthor > len(authorNames) {
+			calendarAuthor = 0
+		}
+		title, days := "all authors", cal.Days
+		if calendarAuthor > 0 {
+			title = authorNames[calendarAuthor-1]
+			days = cal.Authors[title]
+		}
+		writeCalendar(b, cal, days, title, calendarCursor)
+
+		day := calendarCursor.Format(dayFormat)
+		fmt.Fprintf(b, "\n%s (%s): %d commits  [gray]Up/Down day, Left/Right week, Enter show in Commits[-]\n",
+			day, calendarCursor.Format("Mon"), days[day])
+		for _, c := range list {
+			if c.Date.Format(dayFormat) == day && (calendarAuthor == 0 || c.Author == title) {
+				fmt.Fprintf(b, "  %s %s %s\n", c.Hash[:7], c.Author, strings.SplitN(strings.TrimSpace(c.Message), "\n", 2)[0])
+			}
+		}
+		calendar.SetText(b.String())
+	}
+
+	moveCalendarCursor := func(days int) {
+		v := ctrl.State.StatsByRepo[selectedRepo]["contribution_calendar"]
+		if v == nil {
+			return
+		}
+		start, end := calendarRange(v.(stats.Calendar))
+		next := calendarCursor.AddDate(0, 0, days)
+		if next.Before(start) || next.After(end) {
+			return
+		}
+		calendarCursor = next
+		renderCalendar()
+	}
+
+	renderTrend := func() {
+		b := &strings.Builder{}
+		if selectedRepo == "" {
+			fmt.Fprintln(b, "No repository selected")
+		} else {
+			report := (&stats.Trend{Bucket: trendBucket, Policies: opts.Policies}).Calculate(ctrl.State.CommitsByRepo[selectedRepo])
+			writeTrend(b, report.(stats.TrendReport))
+		}
+		trend.SetText(b.String())
+	}
+
+	renderCohorts := func() {
+		b := &strings.Builder{}
+		if selectedRepo == "" {
+			fmt.Fprintln(b, "No repository selected")
+		} else if v := ctrl.State.StatsByRepo[selectedRepo]["cohorts"]; v != nil {
+			writeCohorts(b, v.(stats.CohortReport))
+		}
+		cohorts.SetText(b.String())
+	}
+
+	renderReleases := func() {
+		b := &strings.Builder{}
+		if selectedRepo == "" {
+			fmt.Fprintln(b, "No repository selected")
+		} else if v := ctrl.State.StatsByRepo[selectedRepo]["releases"]; v != nil {
+			writeReleases(b, v.(stats.ReleaseReport))
+		}
+		releases.SetText(b.String())
+	}
+
+	renderBranches := func() {
+		b := &strings.Builder{}
+		if selectedRepo == "" {
+			fmt.Fprintln(b, "No repository selected")
+		} else if report := ctrl.State.BranchesByRepo[selectedRepo]; report != nil {
+			writeBranches(b, report)
+		}
+		branches.SetText(b.String())
+	}
+
+	renderCompare := func() {
+		b := &strings.Builder{}
+		selected := map[string][]analyzer.CommitInfo{}
+		for _, r := range ctrl.State.Repos {
+			if marked[r] {
+				selected[r] = ctrl.State.CommitsByRepo[r]
+			}
+		}
+		if len(selected) < 2 {
+			fmt.Fprintln(b, "Mark two or more repositories with m to compare them")
+		} else {
+			writeComparison(b, stats.Compare(selected, ctrl.Options, trendWidth))
+		}
+		compare.SetText(b.String())
+	}
+
+	renderAll := func() {
+		renderOverview()
+		renderCommits()
+		renderAuthors()
+		renderTimeline()
+		renderHotspots()
+		renderCoupling()
+		renderHeatmap()
+		renderCalendar()
+		renderTrend()
+		renderCohorts()
+		renderReleases()
+		renderBranches()
+		renderCompare()
+	}
+
+	scrollContent := func(delta int) {
+		name, _ := right.GetFrontPage()
+		if name == "calendar" {
+			moveCalendarCursor(delta)
+			return
+		}
+		if name == "coupling" {
+			idx := couplingFiles.GetCurrentItem() + delta
+			if idx >= 0 && idx < couplingFiles.GetItemCount() {
+				couplingFiles.SetCurrentItem(idx)
+			}
+			return
+		}
+		scrollY[name] += delta
+		if scrollY[name] < 0 {
+			scrollY[name] = 0
+		}
+		views[name].ScrollTo(0, scrollY[name])
+	}
+
+	focusContent := func() {
+		focusOnRepos = false
+		name, _ := right.GetFrontPage()
+		app.SetFocus(pageFor(name))
+		right.SetBorderColor(tcell.ColorYellow)
+		repos.SetBorder(true)
+		repos.SetBorderColor(tcell.ColorGray)
+	}
+
+	fillRepos := func() {
+		selected := selectedRepo
+		listedRepos = listedRepos[:0]
+		if _, ok := ctrl.State.StatsByRepo[ui.AllRepositories]; ok && !onlyLocalWork {
+			listedRepos = append(listedRepos, ui.AllRepositories)
+		}
+		for _, r := range ctrl.State.Repos {
+			if status := ctrl.State.StatusByRepo[r]; onlyLocalWork && (status == nil || !status.HasLocalWork()) {
+				continue
+			}
+			listedRepos = append(listedRepos, r)
+		}
+		repos.Clear()
+		for _, r := range listedRepos {
+			repos.AddItem(repoLabel(r, marked[r]), repoStatusLine(ctrl.State.StatusByRepo[r]), 0, nil)
+		}
+		for i, r := range listedRepos {
+			if r == selected {
+				repos.SetCurrentItem(i)
+			}
+		}
+		if len(listedRepos) == 0 {
+			selectedRepo = ""
+		}
+	}
+
 	refresh := func() {
 		statusView.SetText("Analyzing...")
 		go func() {
@@ -259,12 +610,9 @@ func StartTUI(rootPath string) error {
 				})
 			})
 			app.QueueUpdateDraw(func() {
-				repos.Clear()
-				for _, r := range ctrl.State.Repos {
-					repos.AddItem(r, "", 0, nil)
-				}
-				if selectedRepo == "" && len(ctrl.State.Repos) > 0 {
-					selectedRepo = ctrl.State.Repos[0]
+				fillRepos()
+				if selectedRepo == "" && len(listedRepos) > 0 {
+					selectedRepo = listedRepos[0]
 				}
 				statusView.SetText("Idle")
 				focusOnRepos = false
@@ -272,20 +620,14 @@ func StartTUI(rootPath string) error {
 				right.SetBorderColor(tcell.ColorYellow)
 				repos.SetBorder(true)
 				repos.SetBorderColor(tcell.ColorGray)
-				renderOverview()
-				renderCommits()
-				renderAuthors()
-				renderTimeline()
+				renderAll()
 			})
 		}()
 	}
 
 	repos.SetSelectedFunc(func(i int, mainText, secondaryText string, shortcut rune) {
-		selectedRepo = mainText
-		renderOverview()
-		renderCommits()
-		renderAuthors()
-		renderTimeline()
+		selectedRepo = listedRepos[i]
+		renderAll()
 		focusOnRepos = false
 		app.SetFocus(right)
 		right.SetBorderColor(tcell.ColorYellow)
@@ -294,15 +636,12 @@ func StartTUI(rootPath string) error {
 	})
 
 	repos.SetChangedFunc(func(i int, mainText, secondaryText string, shortcut rune) {
-		selectedRepo = mainText
-		scrollOverviewY = 0
-		scrollCommitsY = 0
-		scrollAuthorsY = 0
-		scrollTimelineY = 0
-		renderOverview()
-		renderCommits()
-		renderAuthors()
-		renderTimeline()
+		selectedRepo = listedRepos[i]
+		scrollY = map[string]int{}
+		heatmapAuthor = 0
+		calendarAuthor = 0
+		commitsDay = ""
+		renderAll()
 	})
 
 	layout.SetInputCapture(func(ev *tcell.EventKey) *tcell.EventKey {
@@ -312,67 +651,35 @@ func StartTUI(rootPath string) error {
 		switch ev.Key() {
 		case tcell.KeyUp:
 			if !focusOnRepos {
-				name, _ := right.GetFrontPage()
-				switch name {
-				case "overview":
-					if scrollOverviewY > 0 {
-						scrollOverviewY--
-					}
-					overview.ScrollTo(0, scrollOverviewY)
-				case "commits":
-					if scrollCommitsY > 0 {
-						scrollCommitsY--
-					}
-					commits.ScrollTo(0, scrollCommitsY)
-				case "authors":
-					if scrollAuthorsY > 0 {
-						scrollAuthorsY--
-					}
-					authors.ScrollTo(0, scrollAuthorsY)
-				case "timeline":
-					if scrollTimelineY > 0 {
-						scrollTimelineY--
-					}
-					timeline.ScrollTo(0, scrollTimelineY)
-				}
+				scrollContent(-1)
 				return nil
 			}
 		case tcell.KeyDown:
 			if !focusOnRepos {
-				name, _ := right.GetFrontPage()
-				switch name {
-				case "overview":
-					scrollOverviewY++
-					overview.ScrollTo(0, scrollOverviewY)
-				case "commits":
-					scrollCommitsY++
-					commits.ScrollTo(0, scrollCommitsY)
-				case "authors":
-					scrollAuthorsY++
-					authors.ScrollTo(0, scrollAuthorsY)
-				case "timeline":
-					scrollTimelineY++
-					timeline.ScrollTo(0, scrollTimelineY)
+				scrollContent(1)
+				return nil
+			}
+		case tcell.KeyLeft, tcell.KeyRight:
+			if name, _ := right.GetFrontPage(); !focusOnRepos && name == "calendar" {
+				if ev.Key() == tcell.KeyLeft {
+					moveCalendarCursor(-7)
+				} else {
+					moveCalendarCursor(7)
 				}
 				return nil
 			}
+		case tcell.KeyEnter:
+			if name, _ := right.GetFrontPage(); !focusOnRepos && name == "calendar" {
+				commitsDay = calendarCursor.Format(dayFormat)
+				scrollY["commits"] = 0
+				commits.ScrollToBeginning()
+				renderCommits()
+				right.SwitchToPage("commits")
+				return nil
+			}
 		case tcell.KeyTab, tcell.KeyEsc:
 			if focusOnRepos {
-				focusOnRepos = false
-				name, _ := right.GetFrontPage()
-				switch name {
-				case "overview":
-					app.SetFocus(overview)
-				case "commits":
-					app.SetFocus(commits)
-				case "authors":
-					app.SetFocus(authors)
-				case "timeline":
-					app.SetFocus(timeline)
-				}
-				right.SetBorderColor(tcell.ColorYellow)
-				repos.SetBorder(true)
-				repos.SetBorderColor(tcell.ColorGray)
+				focusContent()
 				return nil
 			}
 		}
@@ -381,21 +688,7 @@ func StartTUI(rootPath string) error {
 			app.Stop()
 		case 'r':
 			if focusOnRepos {
-				focusOnRepos = false
-				name, _ := right.GetFrontPage()
-				switch name {
-				case "overview":
-					app.SetFocus(overview)
-				case "commits":
-					app.SetFocus(commits)
-				case "authors":
-					app.SetFocus(authors)
-				case "timeline":
-					app.SetFocus(timeline)
-				}
-				right.SetBorderColor(tcell.ColorYellow)
-				repos.SetBorder(true)
-				repos.SetBorderColor(tcell.ColorGray)
+				focusContent()
 			} else {
 				focusOnRepos = true
 				app.SetFocus(repos)
@@ -406,14 +699,14 @@ func StartTUI(rootPath string) error {
 			return nil
 		case 'R':
 			refresh()
-		case '1':
-			right.SwitchToPage("overview")
-		case '2':
-			right.SwitchToPage("commits")
-		case '3':
-			right.SwitchToPage("authors")
-		case '4':
-			right.SwitchToPage("timeline")
+		case '1', '2', '3', '4', '5', '6', '7', '8', '9':
+			right.SwitchToPage(pageOrder[ev.Rune()-'1'])
+		case '0':
+			right.SwitchToPage("cohorts")
+		case 'v':
+			right.SwitchToPage("releases")
+		case 'b':
+			right.SwitchToPage("branches")
 		case 'e':
 			defaultPath := filepath.Join(ctrl.State.RootPath, "gitwatcher.json")
 			input := tview.NewInputField().SetLabel("Save path:").SetText(defaultPath)
@@ -465,35 +758,66 @@ func StartTUI(rootPath string) error {
 			case "timeline":
 				sortAscTimeline = !sortAscTimeline
 				renderTimeline()
+			case "hotspots":
+				hotspotSort = (hotspotSort + 1) % 4
+				renderHotspots()
+			case "heatmap":
+				heatmapAuthor++
+				renderHeatmap()
+			case "calendar":
+				calendarAuthor++
+				renderCalendar()
+			case "trend":
+				for i, b := range stats.TrendBuckets {
+					if b == trendBucket {
+						trendBucket = stats.TrendBuckets[(i+1)%len(stats.TrendBuckets)]
+						break
+					}
+				}
+				renderTrend()
+			}
+		case 'f':
+			onlyLocalWork = !onlyLocalWork
+			fillRepos()
+			renderAll()
+			if onlyLocalWork {
+				statusView.SetText(fmt.Sprintf("Showing %d of %d repositories with uncommitted, unpushed or stashed work", len(listedRepos), len(ctrl.State.Repos)))
+			} else {
+				statusView.SetText("Idle")
+			}
+		case 'm':
+			if selectedRepo == "" || selectedRepo == ui.AllRepositories {
+				break
+			}
+			marked[selectedRepo] = !marked[selectedRepo]
+			if !marked[selectedRepo] {
+				delete(marked, selectedRepo)
+			}
+			idx := repos.GetCurrentItem()
+			repos.SetItemText(idx, repoLabel(selectedRepo, marked[selectedRepo]), repoStatusLine(ctrl.State.StatusByRepo[selectedRepo]))
+			renderCompare()
+			if len(marked) >= 2 {
+				right.SwitchToPage("compare")
+			}
+			statusView.SetText(fmt.Sprintf("%d repositories marked for comparison", len(marked)))
+		case 'c':
+			right.SwitchToPage("compare")
+		case 'x':
+			if name, _ := right.GetFrontPage(); name == "commits" && commitsDay != "" {
+				commitsDay = ""
+				renderCommits()
 			}
 		case 'j':
 			if focusOnRepos {
 				idx := repos.GetCurrentItem()
-				if idx+1 < len(ctrl.State.Repos) {
+				if idx+1 < len(listedRepos) {
 					repos.SetCurrentItem(idx + 1)
-					selectedRepo = ctrl.State.Repos[idx+1]
-					renderOverview()
-					renderCommits()
-					renderAuthors()
-					renderTimeline()
+					selectedRepo = listedRepos[idx+1]
+					renderAll()
 				}
 				return nil
 			} else {
-				name, _ := right.GetFrontPage()
-				switch name {
-				case "overview":
-					scrollOverviewY++
-					overview.ScrollTo(0, scrollOverviewY)
-				case "commits":
-					scrollCommitsY++
-					commits.ScrollTo(0, scrollCommitsY)
-				case "authors":
-					scrollAuthorsY++
-					authors.ScrollTo(0, scrollAuthorsY)
-				case "timeline":
-					scrollTimelineY++
-					timeline.ScrollTo(0, scrollTimelineY)
-				}
+				scrollContent(1)
 				return nil
 			}
 		case 'k':
@@ -501,37 +825,12 @@ func StartTUI(rootPath string) error {
 				idx := repos.GetCurrentItem()
 				if idx-1 >= 0 {
 					repos.SetCurrentItem(idx - 1)
-					selectedRepo = ctrl.State.Repos[idx-1]
-					renderOverview()
-					renderCommits()
-					renderAuthors()
-					renderTimeline()
+					selectedRepo = listedRepos[idx-1]
+					renderAll()
 				}
 				return nil
 			} else {
-				name, _ := right.GetFrontPage()
-				switch name {
-				case "overview":
-					if scrollOverviewY > 0 {
-						scrollOverviewY--
-					}
-					overview.ScrollTo(0, scrollOverviewY)
-				case "commits":
-					if scrollCommitsY > 0 {
-						scrollCommitsY--
-					}
-					commits.ScrollTo(0, scrollCommitsY)
-				case "authors":
-					if scrollAuthorsY > 0 {
-						scrollAuthorsY--
-					}
-					authors.ScrollTo(0, scrollAuthorsY)
-				case "timeline":
-					if scrollTimelineY > 0 {
-						scrollTimelineY--
-					}
-					timeline.ScrollTo(0, scrollTimelineY)
-				}
+				scrollContent(-1)
 				return nil
 			}
 		case 'a':
@@ -559,3 +858,22 @@ func StartTUI(rootPath string) error {
 	}
 	return nil
 }
+
+// authorsByCommits lists the authors of commits, most active first.
+func authorsByCommits(commits []analyzer.CommitInfo) []string {
+	counts := make(map[string]int)
+	for _, c := range commits {
+		counts[c.Author]++
+	}
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if counts[names[i]] != counts[names[j]] {
+			return counts[names[i]] > counts[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
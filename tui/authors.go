@@ -0,0 +1,23 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"git-watcher/pkg/stats"
+
+	"github.com/rivo/tview"
+)
+
+// writeAuthorActivity lists commit counts in the order of names with the
+// author's active days, streaks and tenure as extra columns.
+func writeAuthorActivity(b *strings.Builder, names []string, counts map[string]int, streaks map[string]stats.AuthorStreaks) {
+	fmt.Fprintf(b, "%-20s %7s %6s %7s %7s %7s  %-10s  %-10s %6s\n",
+		"Author", "Commits", "Days", "Longest", "Current", "Per day", "First", "Last", "Tenure")
+	for _, name := range names {
+		s := streaks[name]
+		fmt.Fprintf(b, "%-20s %7d %6d %7d %7d %7.2f  %-10s  %-10s %5dd\n",
+			tview.Escape(name), counts[name], s.ActiveDays, s.LongestStreak, s.CurrentStreak,
+			s.CommitsPerActiveDay, s.FirstCommit, s.LastCommit, s.TenureDays)
+	}
+}
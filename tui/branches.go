@@ -0,0 +1,35 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"git-watcher/pkg/analyzer"
+
+	"github.com/rivo/tview"
+)
+
+// writeBranches lists the branches oldest first with their distance from
+// the default branch, marking merged branches green and stale ones red.
+func writeBranches(b *strings.Builder, report *analyzer.BranchReport) {
+	fmt.Fprintf(b, "Default branch %s, %d branches, %d merged, %d stale\n\n",
+		tview.Escape(report.Default), len(report.Branches), report.Merged, report.Stale)
+	if len(report.Branches) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "%-30s %-10s %6s %6s %6s  %-10s %s\n", "Branch", "Last", "Age", "Ahead", "Behind", "Status", "Author: subject")
+	for _, br := range report.Branches {
+		status, color := "unmerged", "white"
+		if br.Merged {
+			status, color = "merged", "green"
+		}
+		if br.Stale {
+			status += "*"
+			color = "red"
+		}
+		fmt.Fprintf(b, "[%s]%-30s[-] %-10s %5.0fd %6d %6d  %-10s %s: %s\n",
+			color, tview.Escape(br.Name), br.LastCommit.Format(dayFormat), br.AgeDays, br.Ahead, br.Behind,
+			status, tview.Escape(br.Author), tview.Escape(br.Subject))
+	}
+	fmt.Fprintln(b, "\n* stale")
+}
@@ -0,0 +1,75 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"git-watcher/pkg/stats"
+
+	"github.com/rivo/tview"
+)
+
+const dayFormat = "2006-01-02"
+
+// calendarRange returns the first and last day covered by cal.
+func calendarRange(cal stats.Calendar) (time.Time, time.Time) {
+	start, _ := time.Parse(dayFormat, cal.Start)
+	end, _ := time.Parse(dayFormat, cal.End)
+	return start, end
+}
+
+// writeCalendar draws days as a contribution grid with one column per week
+// and one row per weekday, Monday first, highlighting the cursor day.
+func writeCalendar(b *strings.Builder, cal stats.Calendar, days map[string]int, title string, cursor time.Time) {
+	start, end := calendarRange(cal)
+	max := 0
+	total := 0
+	for _, v := range days {
+		total += v
+		if v > max {
+			max = v
+		}
+	}
+
+	fmt.Fprintf(b, "Contributions %s to %s: [yellow]%s[-], %d commits (s to change author)\n\n",
+		cal.Start, cal.End, tview.Escape(title), total)
+
+	gridStart := start.AddDate(0, 0, -((int(start.Weekday()) + 6) % 7))
+	weeks := int(end.Sub(gridStart).Hours()/24)/7 + 1
+
+	labels := []byte(strings.Repeat(" ", weeks*2+3))
+	for w := 0; w < weeks; w++ {
+		for d := 0; d < 7; d++ {
+			day := gridStart.AddDate(0, 0, w*7+d)
+			if day.Day() == 1 && !day.Before(start) && !day.After(end) {
+				copy(labels[w*2:], day.Format("Jan"))
+			}
+		}
+	}
+	fmt.Fprintf(b, "     %s\n", strings.TrimRight(string(labels), " "))
+
+	for d := 0; d < 7; d++ {
+		fmt.Fprintf(b, "%-4s ", stats.HeatmapDays[d])
+		for w := 0; w < weeks; w++ {
+			day := gridStart.AddDate(0, 0, w*7+d)
+			if day.Before(start) || day.After(end) {
+				b.WriteString("  ")
+				continue
+			}
+			count := days[day.Format(dayFormat)]
+			if day.Equal(cursor) {
+				bg := "black"
+				if color, ok := heatColor(count, max); ok {
+					bg = color.CSS()
+				}
+				fmt.Fprintf(b, "[white:%s:b]<>[-:-:-]", bg)
+				continue
+			}
+			b.WriteString(heatCell(count, max))
+		}
+		b.WriteString("\n")
+	}
+
+	fmt.Fprintf(b, "\nLess %s More\n", heatLegend())
+}
@@ -0,0 +1,39 @@
+package tui
+
+import (
+	"time"
+)
+
+var sparkLevels = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline renders values as a single line of block characters scaled to
+// the largest value; zero values are left blank.
+func sparkline(values []int) string {
+	max := 0
+	for _, v := range values {
+		if v > max {
+			max = v
+		}
+	}
+	out := make([]rune, len(values))
+	for i, v := range values {
+		if v <= 0 || max == 0 {
+			out[i] = ' '
+			continue
+		}
+		level := v * (len(sparkLevels) - 1) / max
+		out[i] = sparkLevels[level]
+	}
+	return string(out)
+}
+
+// lastMonths returns the n months ending with the month of until, oldest
+// first, formatted as YYYY-MM.
+func lastMonths(until time.Time, n int) []string {
+	start := time.Date(until.Year(), until.Month(), 1, 0, 0, 0, 0, until.Location())
+	months := make([]string, n)
+	for i := 0; i < n; i++ {
+		months[n-1-i] = start.AddDate(0, -i, 0).Format("2006-01")
+	}
+	return months
+}
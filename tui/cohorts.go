@@ -0,0 +1,54 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"git-watcher/pkg/stats"
+
+	"github.com/rivo/tview"
+)
+
+// writeCohorts draws the retention table, one row per monthly cohort of
+// newcomers, followed by the authors who have gone inactive.
+func writeCohorts(b *strings.Builder, report stats.CohortReport) {
+	if len(report.Cohorts) == 0 {
+		fmt.Fprintln(b, "No commits")
+		return
+	}
+
+	fmt.Fprintf(b, "%-8s %4s", "Cohort", "New")
+	for _, h := range report.Horizons {
+		fmt.Fprintf(b, " %6s", stats.HorizonKey(h))
+	}
+	fmt.Fprintf(b, " %8s  %s\n", "Inactive", "Newcomers")
+	for _, c := range report.Cohorts {
+		fmt.Fprintf(b, "%-8s %4d", c.Month, len(c.Newcomers))
+		for _, h := range report.Horizons {
+			retained, ok := c.Retained[stats.HorizonKey(h)]
+			if !ok {
+				fmt.Fprintf(b, " %6s", "-")
+				continue
+			}
+			share := float64(retained) / float64(len(c.Newcomers))
+			fmt.Fprintf(b, " [%s]%5.0f%%[-]", retentionColor(share), share*100)
+		}
+		fmt.Fprintf(b, " %8d  %s\n", c.Inactive, tview.Escape(strings.Join(c.Newcomers, ", ")))
+	}
+
+	fmt.Fprintf(b, "\nInactive authors (%d):\n", len(report.Inactive))
+	for _, a := range report.Inactive {
+		fmt.Fprintf(b, "  %-20s last %s, first %s, %d commits\n", tview.Escape(a.Author), a.LastCommit, a.FirstCommit, a.Commits)
+	}
+}
+
+func retentionColor(share float64) string {
+	switch {
+	case share >= 0.5:
+		return "green"
+	case share >= 0.25:
+		return "yellow"
+	default:
+		return "red"
+	}
+}
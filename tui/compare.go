@@ -0,0 +1,62 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"git-watcher/pkg/stats"
+	"git-watcher/ui"
+
+	"github.com/rivo/tview"
+)
+
+// repoLabel is the text of a repository in the repo list, with a marker
+// when it is marked for comparison.
+func repoLabel(repo string, marked bool) string {
+	if marked {
+		return "[yellow]●[-] " + tview.Escape(ui.RepoName(repo))
+	}
+	return tview.Escape(ui.RepoName(repo))
+}
+
+// writeComparison draws the marked repositories side by side: the summary
+// table, then the hourly distribution and the trend of commits per active
+// author of each as sparklines.
+func writeComparison(b *strings.Builder, c stats.Comparison) {
+	fmt.Fprintf(b, "%-24s %7s %7s %6s %14s %10s %8s\n",
+		"Repository", "Commits", "Authors", "Active", fmt.Sprintf("Per active/%dd", c.WindowDays), "Late-night", "Weekend")
+	for _, r := range c.Repositories {
+		fmt.Fprintf(b, "[yellow]%-24s[-] %7d %7d %6d %14.1f %9.1f%% %7.1f%%\n",
+			tview.Escape(truncate(filepath.Base(r.Repository), 24)), r.Commits, r.Authors, r.ActiveAuthors,
+			r.CommitsPerActiveAuthor, r.LateNightRatio*100, r.WeekendRatio*100)
+	}
+
+	fmt.Fprintf(b, "\n%-24s %s\n", "Hourly distribution", "0h          12h      23h")
+	for _, r := range c.Repositories {
+		hourly := make([]int, len(r.Hourly))
+		for h, share := range r.Hourly {
+			hourly[h] = int(share * 1000)
+		}
+		fmt.Fprintf(b, "%-24s %s\n", tview.Escape(truncate(filepath.Base(r.Repository), 24)), sparkline(hourly))
+	}
+
+	if len(c.Periods) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "\nCommits per active author by %s, %s to %s\n", c.Bucket, c.Periods[0], c.Periods[len(c.Periods)-1])
+	for _, r := range c.Repositories {
+		trend := make([]int, len(r.Trend))
+		for i, v := range r.Trend {
+			trend[i] = int(v * 100)
+		}
+		fmt.Fprintf(b, "%-24s %s  last %.1f\n", tview.Escape(truncate(filepath.Base(r.Repository), 24)), sparkline(trend), r.Trend[len(r.Trend)-1])
+	}
+}
+
+func truncate(s string, n int) string {
+	if len([]rune(s)) <= n {
+		return s
+	}
+	return string([]rune(s)[:n-1]) + "…"
+}
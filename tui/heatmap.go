@@ -0,0 +1,82 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"git-watcher/pkg/stats"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// heatColors grades cells from the quietest to the busiest.
+var heatColors = []tcell.Color{
+	tcell.ColorDarkGreen,
+	tcell.ColorGreen,
+	tcell.ColorYellowGreen,
+	tcell.ColorYellow,
+	tcell.ColorOrange,
+	tcell.ColorRed,
+}
+
+// heatColor picks the shade for value relative to max, reporting false for
+// empty cells. 1 is the quietest shade and max the busiest.
+func heatColor(value, max int) (tcell.Color, bool) {
+	if value <= 0 || max <= 0 {
+		return tcell.ColorDefault, false
+	}
+	level := len(heatColors) - 1
+	if max > 1 {
+		level = (value - 1) * (len(heatColors) - 1) / (max - 1)
+	}
+	if level >= len(heatColors) {
+		level = len(heatColors) - 1
+	}
+	return heatColors[level], true
+}
+
+// heatCell renders a two character wide block shaded by value relative to
+// max; empty cells are drawn as a dim dot.
+func heatCell(value, max int) string {
+	color, ok := heatColor(value, max)
+	if !ok {
+		return "[gray]· [-]"
+	}
+	return colorCell(color)
+}
+
+func colorCell(color tcell.Color) string {
+	return fmt.Sprintf("[:%s]  [:-]", color.CSS())
+}
+
+// heatLegend shows every shade from the quietest to the busiest.
+func heatLegend() string {
+	b := &strings.Builder{}
+	for _, color := range heatColors {
+		b.WriteString(colorCell(color))
+	}
+	return b.String()
+}
+
+func writeHeatmap(b *strings.Builder, heatmap stats.Heatmap, title string) {
+	fmt.Fprintf(b, "Commits by weekday and hour: [yellow]%s[-] (s to change author)\n\n", tview.Escape(title))
+
+	fmt.Fprint(b, "     ")
+	for h := 0; h < 24; h += 3 {
+		fmt.Fprintf(b, "%-6s", fmt.Sprintf("%02d", h))
+	}
+	fmt.Fprintln(b, " Total")
+
+	for d, day := range heatmap.Days {
+		fmt.Fprintf(b, "%-4s ", day)
+		total := 0
+		for h := 0; h < 24; h++ {
+			fmt.Fprint(b, heatCell(heatmap.Matrix[d][h], heatmap.Max))
+			total += heatmap.Matrix[d][h]
+		}
+		fmt.Fprintf(b, " %d\n", total)
+	}
+
+	fmt.Fprintf(b, "\nLess %s More (busiest hour: %d commits)\n", heatLegend(), heatmap.Max)
+}
@@ -0,0 +1,33 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"git-watcher/pkg/stats"
+
+	"github.com/rivo/tview"
+)
+
+func writeHotspots(b *strings.Builder, report stats.HotspotReport, key stats.HotspotSortKey) {
+	if len(report.Files) == 0 {
+		fmt.Fprintln(b, "No file changes recorded")
+		return
+	}
+
+	files := append([]stats.FileHotspot(nil), report.Files...)
+	stats.SortHotspots(files, key)
+	months := lastMonths(report.Until, 12)
+
+	fmt.Fprintf(b, "Hotspots %s to %s, sorted by [yellow]%s[-] (s to change)\n\n",
+		report.Since.Format("2006-01-02"), report.Until.Format("2006-01-02"), key)
+	fmt.Fprintf(b, "%7s %7s %8s %7s  %-12s  %s\n", "Score", "Changes", "Churn", "Authors", "Trend (12m)", "Path")
+	for _, h := range files {
+		counts := make([]int, len(months))
+		for i, m := range months {
+			counts[i] = h.Trend[m]
+		}
+		fmt.Fprintf(b, "%7.2f %7d %8d %7d  %-12s  %s\n",
+			h.Score, h.Changes, h.Churn, h.Authors, sparkline(counts), tview.Escape(h.Path))
+	}
+}
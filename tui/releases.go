@@ -0,0 +1,31 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"git-watcher/pkg/stats"
+
+	"github.com/rivo/tview"
+)
+
+// writeReleases lists the releases newest first with the commits, lines and
+// authors that went into each and the gap since the release before.
+func writeReleases(b *strings.Builder, report stats.ReleaseReport) {
+	if len(report.Releases) == 0 {
+		fmt.Fprintln(b, "No releases (no matching tags in the history)")
+		return
+	}
+
+	fmt.Fprintf(b, "%d releases, %.1f days apart on average, %d unreleased commits (%d lines)\n\n",
+		len(report.Releases), report.MeanDaysBetween, report.Unreleased, report.UnreleasedLines)
+	fmt.Fprintf(b, "%-16s %-10s %6s %7s %8s %7s  %s\n", "Release", "Date", "Days", "Commits", "Lines", "Authors", "Top authors")
+	for _, r := range report.Releases {
+		names := make([]string, 0, len(r.TopAuthors))
+		for _, a := range r.TopAuthors {
+			names = append(names, fmt.Sprintf("%s (%d)", a.Author, a.Commits))
+		}
+		fmt.Fprintf(b, "[yellow]%-16s[-] %-10s %6.1f %7d %8d %7d  %s\n",
+			tview.Escape(r.Name), r.Date, r.DaysSincePrevious, r.Commits, r.Lines, r.Authors, tview.Escape(strings.Join(names, ", ")))
+	}
+}
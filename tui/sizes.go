@@ -0,0 +1,48 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"git-watcher/pkg/stats"
+
+	"github.com/rivo/tview"
+)
+
+// sizeBarWidth is the width of the largest commit size histogram bar.
+const sizeBarWidth = 40
+
+func writeCommitSizes(b *strings.Builder, report stats.CommitSizeReport) {
+	repo := report.Repository
+	fmt.Fprintf(b, "\nCommit size (lines): median %d, p90 %d, p99 %d, max %d\n", repo.Median, repo.P90, repo.P99, repo.Max)
+	max := 0
+	for _, bucket := range repo.Histogram {
+		if bucket.Commits > max {
+			max = bucket.Commits
+		}
+	}
+	for _, bucket := range repo.Histogram {
+		filled := 0
+		if max > 0 {
+			filled = bucket.Commits * sizeBarWidth / max
+		}
+		fmt.Fprintf(b, "%10s %s%s %d\n", bucket.Label,
+			strings.Repeat("█", filled), strings.Repeat(" ", sizeBarWidth-filled), bucket.Commits)
+	}
+
+	if len(report.Outliers) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "Outliers over %d lines:\n", report.OutlierThreshold)
+	for _, o := range report.Outliers {
+		fmt.Fprintf(b, "  [yellow]%s[-] %7d lines %4d files %-11s %s %s\n",
+			shortHash(o.Hash), o.Lines, o.Files, o.Kind, tview.Escape(o.Author), tview.Escape(o.Subject))
+	}
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
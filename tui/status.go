@@ -0,0 +1,46 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"git-watcher/pkg/analyzer"
+
+	"github.com/rivo/tview"
+)
+
+// repoStatusLine summarises a repository's working tree for the repo list:
+// red with uncommitted changes, yellow with unpushed commits or stashes,
+// green when clean.
+func repoStatusLine(status *analyzer.RepoStatus) string {
+	if status == nil {
+		return ""
+	}
+	color := "green"
+	switch {
+	case status.Dirty():
+		color = "red"
+	case status.HasLocalWork():
+		color = "yellow"
+	}
+	parts := []string{fmt.Sprintf("[%s]%s", color, tview.Escape(status.Branch))}
+	if status.Modified > 0 {
+		parts = append(parts, fmt.Sprintf("M%d", status.Modified))
+	}
+	if status.Untracked > 0 {
+		parts = append(parts, fmt.Sprintf("?%d", status.Untracked))
+	}
+	if status.Stashes > 0 {
+		parts = append(parts, fmt.Sprintf("S%d", status.Stashes))
+	}
+	if status.Ahead > 0 {
+		parts = append(parts, fmt.Sprintf("↑%d", status.Ahead))
+	}
+	if status.Behind > 0 {
+		parts = append(parts, fmt.Sprintf("↓%d", status.Behind))
+	}
+	if !status.HasLocalWork() {
+		parts = append(parts, "clean")
+	}
+	return " " + strings.Join(parts, " ") + "[-]"
+}
@@ -0,0 +1,51 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"git-watcher/pkg/stats"
+)
+
+// trendWidth is the number of most recent buckets drawn as sparklines.
+const trendWidth = 60
+
+func writeTrend(b *strings.Builder, report stats.TrendReport) {
+	series := report.Series
+	if len(series) == 0 {
+		fmt.Fprintln(b, "No commits")
+		return
+	}
+	if len(series) > trendWidth {
+		series = series[len(series)-trendWidth:]
+	}
+
+	fmt.Fprintf(b, "Trends by [yellow]%s[-] (s to change bucket), %s to %s, %d of %d buckets\n\n",
+		report.Bucket, series[0].Start, series[len(series)-1].Start, len(series), len(report.Series))
+
+	commits := make([]int, len(series))
+	lines := make([]int, len(series))
+	authors := make([]int, len(series))
+	lateNight := make([]int, len(series))
+	weekend := make([]int, len(series))
+	for i, p := range series {
+		commits[i] = p.Commits
+		lines[i] = int(p.Lines)
+		authors[i] = p.ActiveAuthors
+		lateNight[i] = int(p.LateNightShare * 1000)
+		weekend[i] = int(p.WeekendShare * 1000)
+	}
+	last := series[len(series)-1]
+	fmt.Fprintf(b, "%-16s %s  last %d\n", "Commits", sparkline(commits), last.Commits)
+	fmt.Fprintf(b, "%-16s %s  last %d\n", "Lines changed", sparkline(lines), last.Lines)
+	fmt.Fprintf(b, "%-16s %s  last %d\n", "Active authors", sparkline(authors), last.ActiveAuthors)
+	fmt.Fprintf(b, "%-16s %s  last %.1f%%\n", "Late-night share", sparkline(lateNight), last.LateNightShare*100)
+	fmt.Fprintf(b, "%-16s %s  last %.1f%%\n", "Weekend share", sparkline(weekend), last.WeekendShare*100)
+
+	fmt.Fprintf(b, "\n%-12s %8s %9s %8s %11s %9s\n", "Bucket", "Commits", "Lines", "Authors", "Late-night", "Weekend")
+	for i := len(series) - 1; i >= 0; i-- {
+		p := series[i]
+		fmt.Fprintf(b, "%-12s %8d %9d %8d %10.1f%% %8.1f%%\n",
+			p.Start, p.Commits, p.Lines, p.ActiveAuthors, p.LateNightShare*100, p.WeekendShare*100)
+	}
+}
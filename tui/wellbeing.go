@@ -0,0 +1,59 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"git-watcher/pkg/stats"
+
+	"github.com/rivo/tview"
+)
+
+var wellbeingColors = map[string]string{
+	stats.WellbeingOK:     "green",
+	stats.WellbeingWatch:  "yellow",
+	stats.WellbeingAtRisk: "red",
+}
+
+// writeWellbeing lists authors from the lowest wellbeing score up, with the
+// signals that flagged them.
+func writeWellbeing(b *strings.Builder, report stats.WellbeingReport) {
+	names := make([]string, 0, len(report.Authors))
+	for name := range report.Authors {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		a, c := report.Authors[names[i]], report.Authors[names[j]]
+		if a.Score != c.Score {
+			return a.Score < c.Score
+		}
+		return names[i] < names[j]
+	})
+
+	fmt.Fprintln(b, "\nWellbeing (100 is best):")
+	fmt.Fprintf(b, "%-20s %5s %-8s %6s %6s %8s %7s  %s\n", "Author", "Score", "Level", "Out%", "Streak", "Time off", "Recent", "Flags")
+	for _, name := range names {
+		wb := report.Authors[name]
+		level := fmt.Sprintf("%-8s", wb.Level)
+		fmt.Fprintf(b, "%-20s %5d [%s]%s[-] %5.1f%% %6d %8d %+6.1f%%  %s\n",
+			tview.Escape(name), wb.Score, wellbeingColors[wb.Level], level,
+			wb.OutOfHoursShare*100, wb.LongestStreak, wb.TimeOffCommits,
+			(wb.RecentShare-wb.BaselineShare)*100, strings.Join(wb.Flags, ","))
+	}
+
+	teams := make([]string, 0, len(report.Teams))
+	for team := range report.Teams {
+		teams = append(teams, team)
+	}
+	sort.Strings(teams)
+	for _, team := range teams {
+		tw := report.Teams[team]
+		atRisk := "none"
+		if len(tw.AtRisk) > 0 {
+			atRisk = "[red]" + tview.Escape(strings.Join(tw.AtRisk, ", ")) + "[-]"
+		}
+		fmt.Fprintf(b, "Team %s: score %d, %.1f%% out of hours, at risk: %s\n",
+			tview.Escape(team), tw.Score, tw.OutOfHoursShare*100, atRisk)
+	}
+}
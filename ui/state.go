@@ -7,24 +7,45 @@ import (
     "git-watcher/pkg/stats"
 )
 
+// AllRepositories is the key of the statistics over all repositories
+// together in CommitsByRepo and StatsByRepo. It is not part of Repos and,
+// starting with a NUL byte, cannot be the path of one.
+const AllRepositories = "\x00all"
+
+// RepoName is how a key of CommitsByRepo is shown.
+func RepoName(repo string) string {
+    if repo == AllRepositories {
+        return "All repositories"
+    }
+    return repo
+}
+
 type AppState struct {
     RootPath      string
     Repos         []string
     CommitsByRepo map[string][]analyzer.CommitInfo
     StatsByRepo   map[string]map[string]interface{}
+    // BranchesByRepo holds the branch inventory of each repository.
+    BranchesByRepo map[string]*analyzer.BranchReport
+    // StatusByRepo holds the working tree status of each repository.
+    StatusByRepo  map[string]*analyzer.RepoStatus
     Loading       bool
 }
 
 type Controller struct {
-    State *AppState
+    State   *AppState
+    Options stats.Options
+    Branches analyzer.BranchOptions
 }
 
-func NewController(root string) *Controller {
-    return &Controller{State: &AppState{
+func NewController(root string, opts stats.Options) *Controller {
+    return &Controller{Options: opts, State: &AppState{
         RootPath:      root,
         Repos:         []string{},
         CommitsByRepo: map[string][]analyzer.CommitInfo{},
         StatsByRepo:   map[string]map[string]interface{}{},
+        BranchesByRepo: map[string]*analyzer.BranchReport{},
+        StatusByRepo:  map[string]*analyzer.RepoStatus{},
         Loading:       false,
     }}
 }
@@ -40,16 +61,28 @@ func (c *Controller) Refresh() error {
     c.State.Repos = repos
     c.State.CommitsByRepo = map[string][]analyzer.CommitInfo{}
     c.State.StatsByRepo = map[string]map[string]interface{}{}
+    c.State.BranchesByRepo = map[string]*analyzer.BranchReport{}
+    c.State.StatusByRepo = map[string]*analyzer.RepoStatus{}
+    raw := map[string][]analyzer.CommitInfo{}
     for _, repo := range repos {
         a := analyzer.NewGitAnalyzer(repo)
         commits, err := a.GetCommitInfo()
         if err != nil {
             continue
         }
-        c.State.CommitsByRepo[repo] = commits
-        calc := stats.NewStatsCalculator()
+        commits = stats.ExcludeCommits(commits, c.Options.ExcludeCommits)
+        raw[repo] = commits
+        c.State.CommitsByRepo[repo] = c.Options.TimeZone.Localize(commits)
+        calc := stats.NewStatsCalculatorWithOptions(c.Options)
         c.State.StatsByRepo[repo] = calc.CalculateAll(commits)
+        if branches, err := a.GetBranches(c.Branches); err == nil {
+            c.State.BranchesByRepo[repo] = branches
+        }
+        if status, err := a.GetStatus(); err == nil {
+            c.State.StatusByRepo[repo] = status
+        }
     }
+    c.aggregate(raw)
     c.State.Loading = false
     return nil
 }
@@ -71,6 +104,9 @@ func (c *Controller) RefreshWithProgress(onUpdate func(Progress)) error {
     c.State.Repos = repos
     c.State.CommitsByRepo = map[string][]analyzer.CommitInfo{}
     c.State.StatsByRepo = map[string]map[string]interface{}{}
+    c.State.BranchesByRepo = map[string]*analyzer.BranchReport{}
+    c.State.StatusByRepo = map[string]*analyzer.RepoStatus{}
+    raw := map[string][]analyzer.CommitInfo{}
     for _, repo := range repos {
         a := analyzer.NewGitAnalyzer(repo)
         commits, err := a.GetCommitInfoWithProgress(func(processed int, total int) {
@@ -81,21 +117,63 @@ func (c *Controller) RefreshWithProgress(onUpdate func(Progress)) error {
         if err != nil {
             continue
         }
-        c.State.CommitsByRepo[repo] = commits
-        calc := stats.NewStatsCalculator()
+        commits = stats.ExcludeCommits(commits, c.Options.ExcludeCommits)
+        raw[repo] = commits
+        c.State.CommitsByRepo[repo] = c.Options.TimeZone.Localize(commits)
+        calc := stats.NewStatsCalculatorWithOptions(c.Options)
         c.State.StatsByRepo[repo] = calc.CalculateAll(commits)
+        if branches, err := a.GetBranches(c.Branches); err == nil {
+            c.State.BranchesByRepo[repo] = branches
+        }
+        if status, err := a.GetStatus(); err == nil {
+            c.State.StatusByRepo[repo] = status
+        }
     }
+    c.aggregate(raw)
     c.State.Loading = false
     return nil
 }
 
+// aggregate adds the statistics over the commits of every repository under
+// AllRepositories when there is more than one.
+func (c *Controller) aggregate(raw map[string][]analyzer.CommitInfo) {
+    if len(raw) < 2 {
+        return
+    }
+    commits := stats.AggregateCommits(raw)
+    c.State.CommitsByRepo[AllRepositories] = c.Options.TimeZone.Localize(commits)
+    calc := stats.NewStatsCalculatorWithOptions(c.Options)
+    c.State.StatsByRepo[AllRepositories] = calc.CalculateAll(commits)
+}
+
 func (c *Controller) ExportJSON() ([]byte, error) {
     out := map[string]interface{}{}
+    var aggregate map[string]interface{}
     for repo, commits := range c.State.CommitsByRepo {
-        out[repo] = map[string]interface{}{
+        data := map[string]interface{}{
             "total_commits": len(commits),
             "statistics":    c.State.StatsByRepo[repo],
         }
+        if repo == AllRepositories {
+            data["repositories"] = len(c.State.CommitsByRepo) - 1
+            aggregate = data
+            continue
+        }
+        out[repo] = data
+    }
+    for repo, branches := range c.State.BranchesByRepo {
+        if data, ok := out[repo].(map[string]interface{}); ok {
+            data["branches"] = branches
+        }
+    }
+    for repo, status := range c.State.StatusByRepo {
+        if data, ok := out[repo].(map[string]interface{}); ok {
+            data["status"] = status
+        }
+    }
+    // as with --aggregate, the aggregate is kept apart from the repositories
+    if aggregate != nil {
+        return json.MarshalIndent(map[string]interface{}{"repositories": out, "aggregate": aggregate}, "", "  ")
     }
     return json.MarshalIndent(out, "", "  ")
 }